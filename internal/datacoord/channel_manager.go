@@ -96,6 +96,10 @@ func withEmptyPolicyFactory() ChannelmanagerOpt {
 	return func(c *ChannelManagerImpl) { c.factory = NewEmptyChannelPolicyFactory() }
 }
 
+func withPolicyFactory(factory ChannelPolicyFactory) ChannelmanagerOpt {
+	return func(c *ChannelManagerImpl) { c.factory = factory }
+}
+
 func withCheckerV2() ChannelmanagerOpt {
 	return func(c *ChannelManagerImpl) { c.balanceCheckLoop = c.CheckLoop }
 }
@@ -147,6 +147,7 @@ type Server struct {
 
 	dataNodeCreator        session.DataNodeCreatorFunc
 	rootCoordClientCreator rootCoordCreatorFunc
+	channelPolicyFactory   ChannelPolicyFactory
 	// indexCoord             types.IndexCoord
 
 	// segReferManager  *SegmentReferenceManager
@@ -198,6 +199,13 @@ func WithSegmentManager(manager Manager) Option {
 	}
 }
 
+// WithChannelPolicyFactory returns an Option to set the factory of channel assign and balance policies
+func WithChannelPolicyFactory(factory ChannelPolicyFactory) Option {
+	return func(svr *Server) {
+		svr.channelPolicyFactory = factory
+	}
+}
+
 // CreateServer creates a `Server` instance
 func CreateServer(ctx context.Context, factory dependency.Factory, opts ...Option) *Server {
 	rand.Seed(time.Now().UnixNano())
@@ -488,6 +496,8 @@ func (s *Server) initCluster() error {
 	channelManagerOpts := []ChannelmanagerOpt{withCheckerV2()}
 	if streamingutil.IsStreamingServiceEnabled() {
 		channelManagerOpts = append(channelManagerOpts, withEmptyPolicyFactory())
+	} else if s.channelPolicyFactory != nil {
+		channelManagerOpts = append(channelManagerOpts, withPolicyFactory(s.channelPolicyFactory))
 	}
 	s.channelManager, err = NewChannelManager(s.watchClient, s.handler, s.sessionManager, s.idAllocator, channelManagerOpts...)
 	if err != nil {
@@ -125,6 +125,10 @@ func (s *Server) ListAliases(ctx context.Context, request *milvuspb.ListAliasesR
 	return s.rootCoord.ListAliases(ctx, request)
 }
 
+func (s *Server) GetRootCoord() types.RootCoordComponent {
+	return s.rootCoord
+}
+
 // NewServer create a new RootCoord grpc server.
 func NewServer(ctx context.Context, factory dependency.Factory) (*Server, error) {
 	ctx1, cancel := context.WithCancel(ctx)
@@ -207,6 +207,11 @@ func (s *Server) Health(ctx context.Context) commonpb.StateCode {
 	return resp.GetState().StateCode
 }
 
+// GetAddress returns the address the streamingnode is registered with, which is available after Prepare.
+func (s *Server) GetAddress() string {
+	return s.listener.Address()
+}
+
 func (s *Server) init() (err error) {
 	log := log.Ctx(s.ctx)
 	defer func() {
@@ -117,6 +117,7 @@ type Core struct {
 
 	idAllocator  allocator.Interface
 	tsoAllocator tso2.Allocator
+	tsoClock     func() time.Time
 
 	dataCoord  types.DataCoordClient
 	queryCoord types.QueryCoordClient
@@ -315,6 +316,11 @@ func (c *Core) SetEtcdClient(etcdClient *clientv3.Client) {
 	c.etcdCli = etcdClient
 }
 
+// SetTSOClock sets the clock read by the tso allocator of Core instead of the system time, it's used in tests only
+func (c *Core) SetTSOClock(clock func() time.Time) {
+	c.tsoClock = clock
+}
+
 // SetTiKVClient sets the tikvCli of Core
 func (c *Core) SetTiKVClient(client *txnkv.Client) {
 	c.tikvCli = client
@@ -430,6 +436,9 @@ func (c *Core) initTSOAllocator(initCtx context.Context) error {
 		tsoKV = tsoutil2.NewTSOKVBase(c.etcdCli, Params.EtcdCfg.KvRootPath.GetValue(), globalIDAllocatorSubPath)
 	}
 	tsoAllocator := tso2.NewGlobalTSOAllocator(globalTSOAllocatorKey, tsoKV)
+	if c.tsoClock != nil {
+		tsoAllocator.SetClock(c.tsoClock)
+	}
 	if err := tsoAllocator.Initialize(); err != nil {
 		return err
 	}
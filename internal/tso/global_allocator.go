@@ -95,6 +95,12 @@ func (gta *GlobalTSOAllocator) SetLimitMaxLogic(flag bool) {
 	gta.LimitMaxLogic = flag
 }
 
+// SetClock replaces the system time read by the allocator with clock, e.g. to simulate clock jumps in tests.
+// It shall be called before Initialize.
+func (gta *GlobalTSOAllocator) SetClock(clock func() time.Time) {
+	gta.tso.clock = clock
+}
+
 // UpdateTSO is used to update the TSO in memory and the time window in etcd.
 func (gta *GlobalTSOAllocator) UpdateTSO() error {
 	return gta.tso.UpdateTimestamp()
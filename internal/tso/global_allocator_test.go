@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
 
 	tsoutil2 "github.com/milvus-io/milvus/internal/util/tsoutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/etcd"
@@ -266,3 +267,37 @@ func TestGlobalTSOAllocator_load(t *testing.T) {
 	assert.True(t, ts2 >= target)
 	assert.True(t, curTime2.UnixNano() >= nextTime.UnixNano())
 }
+
+func TestGlobalTSOAllocator_ClockJump(t *testing.T) {
+	endpoints := os.Getenv("ETCD_ENDPOINTS")
+	if endpoints == "" {
+		endpoints = "localhost:2379"
+	}
+	etcdEndpoints := strings.Split(endpoints, ",")
+	etcdCli, err := etcd.GetRemoteEtcdClient(etcdEndpoints)
+	assert.NoError(t, err)
+	defer etcdCli.Close()
+	etcdKV := tsoutil2.NewTSOKVBase(etcdCli, "/test/root/kv", "tsoTest")
+
+	offset := atomic.NewDuration(0)
+	allocator := NewGlobalTSOAllocator("timestamp", etcdKV)
+	allocator.SetClock(func() time.Time {
+		return time.Now().Add(offset.Load())
+	})
+	err = allocator.Initialize()
+	assert.NoError(t, err)
+	before, err := allocator.AllocOne()
+	assert.NoError(t, err)
+
+	offset.Store(-time.Hour)
+	err = allocator.UpdateTSO()
+	assert.NoError(t, err)
+
+	// the timestamp keeps increasing while the clock is turned backward
+	after, err := allocator.AllocOne()
+	assert.NoError(t, err)
+	assert.Greater(t, after, before)
+	beforePhysical, _ := tsoutil.ParseTS(before)
+	afterPhysical, _ := tsoutil.ParseTS(after)
+	assert.False(t, afterPhysical.Before(beforePhysical))
+}
@@ -69,11 +69,20 @@ type timestampOracle struct {
 	// TODO: remove saveInterval
 	saveInterval  time.Duration
 	maxResetTSGap func() time.Duration
+	// clock returns the system time, time.Now is used if it's nil.
+	clock func() time.Time
 	// For tso, set after the PD becomes a leader.
 	TSO           unsafe.Pointer
 	lastSavedTime atomic.Value
 }
 
+func (t *timestampOracle) now() time.Time {
+	if t.clock != nil {
+		return t.clock()
+	}
+	return time.Now()
+}
+
 func (t *timestampOracle) loadTimestamp() (time.Time, error) {
 	strData, err := t.txnKV.Load(context.TODO(), t.key)
 	if err != nil {
@@ -106,7 +115,7 @@ func (t *timestampOracle) InitTimestamp() error {
 	if err != nil {
 		return err
 	}
-	next := time.Now()
+	next := t.now()
 
 	// If the current system time minus the saved etcd timestamp is less than `updateTimestampGuard`,
 	// the timestamp allocation will start from the saved etcd timestamp temporarily.
@@ -171,12 +180,16 @@ func (t *timestampOracle) ResetUserTimestamp(tso uint64) error {
 // 3. The physical time is always less than the saved timestamp.
 func (t *timestampOracle) UpdateTimestamp() error {
 	prev := (*atomicObject)(atomic.LoadPointer(&t.TSO))
-	now := time.Now()
+	now := t.now()
 
 	jetLag := typeutil.SubTimeByWallClock(now, prev.physical)
 	if jetLag > 3*UpdateTimestampStep {
 		log.Ctx(context.TODO()).WithRateGroup("tso", 1, 60).RatedWarn(60.0, "clock offset is huge, check network latency and clock skew", zap.Duration("jet-lag", jetLag),
 			zap.Time("prev-physical", prev.physical), zap.Time("now", now))
+	} else if jetLag < -3*UpdateTimestampStep {
+		// the physical time never goes back, it's held until the system time catches up
+		log.Ctx(context.TODO()).WithRateGroup("tso.backward", 1, 60).RatedWarn(60.0, "system time jumps backward, hold the physical time", zap.Duration("jet-lag", jetLag),
+			zap.Time("prev-physical", prev.physical), zap.Time("now", now))
 	}
 
 	var next time.Time
@@ -217,7 +230,7 @@ func (t *timestampOracle) UpdateTimestamp() error {
 // ResetTimestamp is used to reset the timestamp.
 func (t *timestampOracle) ResetTimestamp() {
 	zero := &atomicObject{
-		physical: time.Now(),
+		physical: t.now(),
 	}
 	// atomic unsafe pointer
 	/* #nosec G103 */
@@ -703,6 +703,11 @@ func (s *Session) GoingStop() error {
 		return errors.New("this session has disconnected")
 	}
 
+	// the key may belong to another session with the same server id if the registration failed
+	if !s.Registered() {
+		return errors.New("the session hasn't been registered")
+	}
+
 	completeKey := s.getCompleteKey()
 	resp, err := s.etcdCli.Get(s.ctx, completeKey, clientv3.WithCountOnly())
 	if err != nil {
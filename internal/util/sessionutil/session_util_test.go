@@ -893,6 +893,12 @@ func (s *SessionSuite) TestGoingStop() {
 	sess.Init("test", "normal", false, false)
 	sess.Register()
 
+	// a session failed to register with the same server id shall not touch the key of the registered one
+	sconflict := NewSessionWithEtcd(ctx, s.metaRoot, s.client)
+	sconflict.Init("test", "conflict", false, false)
+	sconflict.ServerID = sess.ServerID
+	sconflict.LeaseID = sess.LeaseID
+
 	cases := []struct {
 		tag         string
 		input       *Session
@@ -901,6 +907,7 @@ func (s *SessionSuite) TestGoingStop() {
 		{"nil", nil, true},
 		{"not_inited", &Session{}, true},
 		{"disconnected", sdisconnect, true},
+		{"not_registered", sconflict, true},
 		{"normal", sess, false},
 	}
 
@@ -307,6 +307,11 @@ func (m *Manager) ForbidUpdate(key string) {
 	m.forbiddenKeys.Insert(formatKey(key))
 }
 
+// IsForbidden returns whether the update events of the key are ignored
+func (m *Manager) IsForbidden(key string) bool {
+	return m.forbiddenKeys.Contain(formatKey(key))
+}
+
 func (m *Manager) UpdateSourceOptions(opts ...Option) {
 	var options Options
 	for _, opt := range opts {
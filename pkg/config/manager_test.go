@@ -109,7 +109,9 @@ func TestBasic(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, value, "aaa")
 
+	assert.False(t, mgr.IsForbidden("a.b"))
 	mgr.ForbidUpdate("a.b")
+	assert.True(t, mgr.IsForbidden("a.b"))
 	mgr.OnEvent(&Event{
 		EventSource: envSource.GetSourceName(),
 		EventType:   UpdateType,
@@ -5189,6 +5189,25 @@ func (params *ComponentParam) Reset(key string) error {
 	return params.baseTable.Reset(key)
 }
 
+// Refresh saves the value of key like Save, then dispatches the update event of key to its watchers as a config source does,
+// so that the components watching key reload it without restart. Keys forbidden to update at runtime are rejected.
+func (params *ComponentParam) Refresh(key string, value string) error {
+	if params.baseTable.mgr.IsForbidden(key) {
+		return fmt.Errorf("config %s is forbidden to update at runtime", key)
+	}
+	if err := params.baseTable.Save(key, value); err != nil {
+		return err
+	}
+	params.baseTable.mgr.Dispatcher.Dispatch(&config.Event{
+		EventSource: "runtime",
+		EventType:   config.UpdateType,
+		Key:         key,
+		Value:       value,
+		HasUpdated:  true,
+	})
+	return nil
+}
+
 func (params *ComponentParam) GetWithDefault(key string, dft string) string {
 	return params.baseTable.GetWithDefault(key, dft)
 }
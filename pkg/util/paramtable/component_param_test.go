@@ -665,6 +665,27 @@ func TestForbiddenItem(t *testing.T) {
 	assert.Equal(t, "by-dev", params.CommonCfg.ClusterPrefix.GetValue())
 }
 
+func TestRefreshParam(t *testing.T) {
+	Init()
+	params := Get()
+
+	key := params.DataCoordCfg.EnableAutoCompaction.Key
+	defer params.Reset(key)
+	var refreshed string
+	handler := config.NewHandler("TestRefreshParam", func(event *config.Event) {
+		refreshed = params.DataCoordCfg.EnableAutoCompaction.GetValue()
+	})
+	params.Watch(key, handler)
+	defer params.Unwatch(key, handler)
+
+	assert.NoError(t, params.Refresh(key, "false"))
+	assert.False(t, params.DataCoordCfg.EnableAutoCompaction.GetAsBool())
+	assert.Equal(t, "false", refreshed)
+
+	assert.Error(t, params.Refresh(params.CommonCfg.ClusterPrefix.Key, "new-cluster"))
+	assert.Equal(t, "by-dev", params.CommonCfg.ClusterPrefix.GetValue())
+}
+
 func TestCachedParam(t *testing.T) {
 	Init()
 	params := Get()
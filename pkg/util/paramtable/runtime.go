@@ -102,6 +102,10 @@ func SetLocalComponentEnabled(component string) {
 	runtimeParam.components.Insert(component)
 }
 
+func SetLocalComponentDisabled(component string) {
+	runtimeParam.components.Remove(component)
+}
+
 func IsLocalComponentEnabled(component string) bool {
 	return runtimeParam.components.Contain(component)
 }
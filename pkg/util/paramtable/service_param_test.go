@@ -249,4 +249,8 @@ func TestRuntimConfig(t *testing.T) {
 
 	SetLocalComponentEnabled(typeutil.QueryCoordRole)
 	assert.True(t, IsLocalComponentEnabled(typeutil.QueryCoordRole))
+
+	SetLocalComponentDisabled(typeutil.QueryCoordRole)
+	assert.False(t, IsLocalComponentEnabled(typeutil.QueryCoordRole))
+	assert.True(t, IsLocalComponentEnabled(typeutil.QueryNodeRole))
 }
@@ -0,0 +1,81 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type AuthSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *AuthSuite) SetupTest() {
+	// root takes the default password of Milvus
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithAuthEnabled(""))
+}
+
+func (s *AuthSuite) TestAuthenticatedClients() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*2)
+	defer cancel()
+
+	const dim = 128
+	collectionName := "TestAuthenticatedClients" + funcutil.GenRandomStr()
+
+	s.True(paramtable.Get().CommonCfg.AuthorizationEnabled.GetAsBool())
+
+	// MilvusClient carries the credential of root
+	marshaledSchema, err := proto.Marshal(integration.ConstructSchema(collectionName, dim, true))
+	s.NoError(err)
+	createStatus, err := c.MilvusClient.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      1,
+	})
+	s.NoError(merr.CheckRPCCall(createStatus, err))
+	showResp, err := c.MilvusClient.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{})
+	s.NoError(merr.CheckRPCCall(showResp, err))
+	s.Contains(showResp.GetCollectionNames(), collectionName)
+
+	// while calls without any credential are rejected
+	unauthenticated, err := c.GetUnauthenticatedClient()
+	s.Require().NoError(err)
+	_, err = unauthenticated.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{})
+	s.Error(err)
+	s.Equal(codes.Unauthenticated, status.Code(err))
+
+	log.Info("TestAuthenticatedClients succeed")
+}
+
+func TestAuth(t *testing.T) {
+	suite.Run(t, new(AuthSuite))
+}
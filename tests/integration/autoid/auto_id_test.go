@@ -0,0 +1,80 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoid
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type AutoIDSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *AutoIDSuite) TestGeneratedPKs() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim      = 128
+		dbName   = ""
+		rowNum   = 1000
+		batchNum = 3
+	)
+	collectionName := "TestGeneratedPKs" + funcutil.GenRandomStr()
+
+	s.Require().NoError(c.CreateAutoIDCollection(ctx, dbName, collectionName, dim, common.DefaultShardsNum))
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	var pks []int64
+	for i := 0; i < batchNum; i++ {
+		batch, err := c.InsertAutoID(ctx, dbName, collectionName, rowNum, dim)
+		s.Require().NoError(err)
+		pks = append(pks, batch...)
+	}
+	// primary keys are unique across inserts as well
+	s.Empty(lo.FindDuplicates(pks))
+
+	// and every generated primary key is queryable
+	s.NoError(c.VerifyCollectionPKs(ctx, dbName, collectionName, integration.Int64Field, pks))
+
+	log.Info("TestGeneratedPKs succeed")
+}
+
+func TestAutoID(t *testing.T) {
+	suite.Run(t, new(AutoIDSuite))
+}
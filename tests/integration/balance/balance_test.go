@@ -153,7 +153,8 @@ func (s *BalanceTestSuit) initCollection(collectionName string, replica int, cha
 	log.Info("index create done")
 
 	for i := 1; i < replica; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	// load
@@ -175,7 +176,8 @@ func (s *BalanceTestSuit) TestBalanceOnSingleReplica() {
 
 	ctx := context.Background()
 	// add a querynode, expected balance happens
-	qn := s.Cluster.AddQueryNode()
+	qn, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
 
 	// check segment number on new querynode
 	s.Eventually(func() bool {
@@ -213,8 +215,10 @@ func (s *BalanceTestSuit) TestBalanceOnMultiReplica() {
 	s.Len(resp.Replicas, 2)
 
 	// add a querynode, expected balance happens
-	qn1 := s.Cluster.AddQueryNode()
-	qn2 := s.Cluster.AddQueryNode()
+	qn1, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
+	qn2, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
 
 	// check segment num on new query node
 	s.Eventually(func() bool {
@@ -256,8 +260,10 @@ func (s *BalanceTestSuit) TestNodeDown() {
 	s.initCollection(name, 1, 2, 15, 2000, 500)
 
 	// then we add 2 query node, after balance happens, expected each node have 10 segments
-	qn1 := s.Cluster.AddQueryNode()
-	qn2 := s.Cluster.AddQueryNode()
+	qn1, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
+	qn2, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
 
 	// check segment num on new query node
 	s.Eventually(func() bool {
@@ -155,7 +155,8 @@ func (s *ChannelExclusiveBalanceSuit) initCollection(collectionName string, repl
 	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
 
 	for i := 1; i < replica; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	// load
@@ -182,7 +183,8 @@ func (s *ChannelExclusiveBalanceSuit) TestBalanceOnSingleReplica() {
 	qnList := make([]*grpcquerynode.Server, 0)
 	// add a querynode, expected balance happens
 	for i := 1; i < channelCount*channelNodeCount; i++ {
-		qn := s.Cluster.AddQueryNode()
+		qn, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 		qnList = append(qnList, qn)
 	}
 
@@ -213,8 +215,10 @@ func (s *ChannelExclusiveBalanceSuit) TestBalanceOnSingleReplica() {
 	}, 60*time.Second, 3*time.Second)
 
 	// add two new query node and stop two old querynode
-	s.Cluster.AddQueryNode()
-	s.Cluster.AddQueryNode()
+	_, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
+	_, err = s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
 	qnList[0].Stop()
 	qnList[1].Stop()
 
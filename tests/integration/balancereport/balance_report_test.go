@@ -0,0 +1,99 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package balancereport
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type BalanceReportSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *BalanceReportSuite) TestBalanceReport() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+	)
+	collectionName := "TestBalanceReport" + funcutil.GenRandomStr()
+
+	// one querynode per replica
+	_, err := c.AddQueryNode()
+	s.Require().NoError(err)
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       2,
+		SegmentNum:       2,
+		RowNumPerSegment: 2000,
+		Dim:              dim,
+		ReplicaNumber:    2,
+	})
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	report, err := c.BalanceReport(ctx, collectionName)
+	s.NoError(err)
+	log.Info("balance report", zap.String("report", report))
+
+	replicasResp, err := c.Proxy.GetReplicas(ctx, &milvuspb.GetReplicasRequest{
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(replicasResp, err))
+	s.Len(replicasResp.GetReplicas(), 2)
+	for _, replica := range replicasResp.GetReplicas() {
+		s.Contains(report, fmt.Sprintf("replica %d:", replica.GetReplicaID()))
+	}
+
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+	s.Len(describeResp.GetVirtualChannelNames(), 2)
+	for _, channel := range describeResp.GetVirtualChannelNames() {
+		s.Contains(report, fmt.Sprintf("shard %s:", channel))
+	}
+
+	log.Info("TestBalanceReport succeed")
+}
+
+func TestBalanceReport(t *testing.T) {
+	suite.Run(t, new(BalanceReportSuite))
+}
@@ -0,0 +1,127 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchsearch
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type BatchedSearchSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *BatchedSearchSuite) TestEachQueryHitsItsOwnRow() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 2000
+		nq     = 5
+		topk   = 10
+	)
+	collectionName := "TestEachQueryHitsItsOwnRow" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	// take the vectors of nq distinct rows as the queries
+	queryResult, err := c.Proxy.Query(ctx, &milvuspb.QueryRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		OutputFields:   []string{integration.Int64Field, integration.FloatVecField},
+		QueryParams: []*commonpb.KeyValuePair{
+			{Key: integration.LimitKey, Value: strconv.Itoa(nq)},
+		},
+		ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+	})
+	s.Require().NoError(merr.CheckRPCCall(queryResult, err))
+	var (
+		pks     []int64
+		vectors []float32
+	)
+	for _, fieldData := range queryResult.GetFieldsData() {
+		switch fieldData.GetFieldName() {
+		case integration.Int64Field:
+			pks = fieldData.GetScalars().GetLongData().GetData()
+		case integration.FloatVecField:
+			vectors = fieldData.GetVectors().GetFloatVector().GetData()
+		}
+	}
+	s.Require().Len(pks, nq)
+	s.Require().Len(vectors, nq*dim)
+
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, "", integration.FloatVecField,
+		schemapb.DataType_FloatVector, nil, metric.L2, integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2), nq, dim, topk, -1)
+	values := make([][]byte, 0, nq)
+	for i := 0; i < nq; i++ {
+		values = append(values, typeutil.Float32ArrayToBytes(vectors[i*dim:(i+1)*dim]))
+	}
+	searchReq.PlaceholderGroup, err = proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{
+			{
+				Tag:    "$0",
+				Type:   commonpb.PlaceholderType_FloatVector,
+				Values: values,
+			},
+		},
+	})
+	s.Require().NoError(err)
+
+	searchResult, err := c.CheckBatchedSearch(ctx, searchReq)
+	s.Require().NoError(err)
+	topks := searchResult.GetResults().GetTopks()
+	ids := searchResult.GetResults().GetIds().GetIntId().GetData()
+	var offset int64
+	for i, pk := range pks {
+		s.Require().EqualValues(topk, topks[i])
+		// the nearest neighbor of a query is the row it's taken from
+		s.Equal(pk, ids[offset], "query %d", i)
+		offset += topks[i]
+	}
+
+	log.Info("TestEachQueryHitsItsOwnRow succeed")
+}
+
+func TestBatchedSearch(t *testing.T) {
+	suite.Run(t, new(BatchedSearchSuite))
+}
@@ -155,7 +155,8 @@ func (s *BloomFilterTestSuit) initCollection(collectionName string, replica int,
 	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
 
 	for i := 1; i < replica; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	// load
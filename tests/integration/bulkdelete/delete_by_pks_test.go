@@ -0,0 +1,109 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulkdelete
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type DeleteByPKsSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *DeleteByPKsSuite) flush(ctx context.Context, collectionName string) {
+	c := s.Cluster
+	flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+		CollectionNames: []string{collectionName},
+	})
+	s.Require().NoError(merr.CheckRPCCall(flushResp, err))
+	s.Require().NoError(c.WaitForAllFlushed(ctx, []string{collectionName}))
+}
+
+func (s *DeleteByPKsSuite) TestDeleteByPKList() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim       = 128
+		dbName    = ""
+		rowNum    = 1000
+		deleteNum = 100
+	)
+	collectionName := "TestDeleteByPKList" + funcutil.GenRandomStr()
+
+	s.Require().NoError(c.CreateAutoIDCollection(ctx, dbName, collectionName, dim, common.DefaultShardsNum))
+	pks, err := c.InsertAutoID(ctx, dbName, collectionName, rowNum, dim)
+	s.Require().NoError(err)
+	s.flush(ctx, collectionName)
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	deleted, remaining := pks[:deleteNum], pks[deleteNum:]
+	deleteResult, err := c.DeleteByPKs(ctx, collectionName, deleted)
+	s.Require().NoError(err)
+	s.EqualValues(deleteNum, deleteResult.GetDeleteCnt())
+	s.flush(ctx, collectionName)
+
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collectionName,
+	})
+	s.Require().NoError(merr.CheckRPCCall(describeResp, err))
+	compactResp, err := c.Proxy.ManualCompaction(ctx, &milvuspb.ManualCompactionRequest{
+		CollectionID: describeResp.GetCollectionID(),
+	})
+	s.Require().NoError(merr.CheckRPCCall(compactResp, err))
+	s.Eventually(func() bool {
+		resp, err := c.Proxy.GetCompactionState(ctx, &milvuspb.GetCompactionStateRequest{
+			CompactionID: compactResp.GetCompactionID(),
+		})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return false
+		}
+		return resp.GetState() == commonpb.CompactionState_Completed
+	}, time.Minute*2, time.Second)
+
+	// exactly the deleted rows are gone
+	s.NoError(c.VerifyPKsDeleted(ctx, collectionName, deleted))
+	s.NoError(c.VerifyCollectionPKs(ctx, dbName, collectionName, integration.Int64Field, remaining))
+
+	log.Info("TestDeleteByPKList succeed")
+}
+
+func TestDeleteByPKs(t *testing.T) {
+	suite.Run(t, new(DeleteByPKsSuite))
+}
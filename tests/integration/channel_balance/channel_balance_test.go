@@ -57,7 +57,8 @@ func (s *ChannelBalanceSuite) TestReAssignmentWithDNReboot() {
 
 	// reboot DN
 	s.Cluster.StopAllDataNodes()
-	s.Cluster.AddDataNode()
+	_, err := s.Cluster.AddDataNode()
+	s.Require().NoError(err)
 
 	// check channels reassignments by insert/delete & flush
 	lo.ForEach(collections, func(collection string, _ int) {
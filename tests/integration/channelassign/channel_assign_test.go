@@ -0,0 +1,128 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channelassign
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	grpcdatanode "github.com/milvus-io/milvus/internal/distributed/datanode"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type ChannelAssignSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *ChannelAssignSuite) SetupSuite() {
+	paramtable.Init()
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.ChannelCheckInterval.Key, "1")
+
+	s.Require().NoError(s.SetupEmbedEtcd())
+}
+
+func (s *ChannelAssignSuite) TearDownSuite() {
+	paramtable.Get().Reset(paramtable.Get().DataCoordCfg.ChannelCheckInterval.Key)
+
+	s.MiniClusterSuite.TearDownSuite()
+}
+
+func (s *ChannelAssignSuite) SetupTest() {
+	// pin every channel to the datanode with the largest id
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithChannelAssignment(func(channel string, candidates []int64) int64 {
+		return lo.Max(candidates)
+	}))
+}
+
+func (s *ChannelAssignSuite) getNodeID(ctx context.Context, node *grpcdatanode.Server) int64 {
+	states, err := node.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+	s.Require().NoError(merr.CheckRPCCall(states, err))
+	return states.GetState().GetNodeID()
+}
+
+func (s *ChannelAssignSuite) TestPinnedChannelAssignment() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim       = 128
+		dbName    = ""
+		shardsNum = 2
+	)
+	collectionName := "TestPinnedChannelAssignment" + funcutil.GenRandomStr()
+
+	primaryID := s.getNodeID(ctx, c.DataNode)
+	extra, err := c.AddDataNode()
+	s.Require().NoError(err)
+	extraID := s.getNodeID(ctx, extra)
+	s.Require().Greater(extraID, primaryID)
+
+	schema := integration.ConstructSchema(collectionName, dim, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      shardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+	channels := describeResp.GetVirtualChannelNames()
+	s.Require().Len(channels, shardsNum)
+
+	assignedTo := func(nodeID int64) func() bool {
+		return func() bool {
+			assignment, err := c.GetChannelAssignment(ctx)
+			if err != nil {
+				return false
+			}
+			return lo.EveryBy(channels, func(channel string) bool {
+				return assignment[channel] == nodeID
+			})
+		}
+	}
+
+	// all channels are pinned to the extra datanode
+	s.Eventually(assignedTo(extraID), time.Minute, time.Second)
+
+	// channels shall be reassigned to the surviving datanode once the extra one is gone
+	s.NoError(extra.Stop())
+	s.Eventually(assignedTo(primaryID), time.Minute, time.Second)
+
+	log.Info("TestPinnedChannelAssignment succeed")
+}
+
+func TestChannelAssign(t *testing.T) {
+	suite.Run(t, new(ChannelAssignSuite))
+}
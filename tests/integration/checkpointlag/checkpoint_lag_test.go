@@ -0,0 +1,113 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpointlag
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type CheckpointLagSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *CheckpointLagSuite) SetupSuite() {
+	paramtable.Init()
+	// keep the checkpoints of idle channels fresh
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.UpdateChannelCheckpointInterval.Key, "1")
+	paramtable.Get().Save(paramtable.Get().DataNodeCfg.ChannelCheckpointUpdateTickInSeconds.Key, "1")
+
+	s.Require().NoError(s.SetupEmbedEtcd())
+}
+
+func (s *CheckpointLagSuite) TearDownSuite() {
+	paramtable.Get().Reset(paramtable.Get().DataNodeCfg.UpdateChannelCheckpointInterval.Key)
+	paramtable.Get().Reset(paramtable.Get().DataNodeCfg.ChannelCheckpointUpdateTickInSeconds.Key)
+
+	s.MiniClusterSuite.TearDownSuite()
+}
+
+func (s *CheckpointLagSuite) SetupTest() {
+	// the data of the burst is flushed once the channel idles
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithSegmentIdleSeal(5 * time.Second))
+}
+
+func (s *CheckpointLagSuite) maxLag(ctx context.Context, collectionName string) (time.Duration, bool) {
+	lags, err := s.Cluster.GetChannelCheckpointLag(ctx, collectionName)
+	if err != nil {
+		log.Warn("fail to get channel checkpoint lag", zap.Error(err))
+		return 0, false
+	}
+	log.Info("channel checkpoint lag", zap.Any("lags", lags))
+	return lo.Max(lo.Values(lags)), true
+}
+
+func (s *CheckpointLagSuite) TestLagOfBurst() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim       = 128
+		dbName    = ""
+		shardsNum = 2
+		rowNum    = 1000
+		batchNum  = 10
+		idleLag   = 5 * time.Second
+		spikeLag  = 8 * time.Second
+	)
+	collectionName := "TestLagOfBurst" + funcutil.GenRandomStr()
+	s.Require().NoError(c.CreateAutoIDCollection(ctx, dbName, collectionName, dim, shardsNum))
+
+	lagBelow := func(d time.Duration) func() bool {
+		return func() bool {
+			lag, ok := s.maxLag(ctx, collectionName)
+			return ok && lag < d
+		}
+	}
+	s.Eventually(lagBelow(idleLag), time.Minute, time.Second)
+
+	// the checkpoints stay before the burst until its data is flushed
+	for i := 0; i < batchNum; i++ {
+		_, err := c.InsertAutoID(ctx, dbName, collectionName, rowNum, dim)
+		s.Require().NoError(err)
+		time.Sleep(time.Second)
+	}
+	s.Eventually(func() bool {
+		lag, ok := s.maxLag(ctx, collectionName)
+		return ok && lag >= spikeLag
+	}, 10*time.Second, 500*time.Millisecond)
+
+	// and catch up once the channels idle
+	s.Eventually(lagBelow(idleLag), 2*time.Minute, time.Second)
+
+	log.Info("TestLagOfBurst succeed")
+}
+
+func TestCheckpointLag(t *testing.T) {
+	suite.Run(t, new(CheckpointLagSuite))
+}
@@ -0,0 +1,81 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package churn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type CollectionChurnSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *CollectionChurnSuite) SetupSuite() {
+	paramtable.Init()
+	// let datacoord clean the meta of dropped segments right away
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.GCInterval.Key, "1")
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.GCDropTolerance.Key, "1")
+
+	s.Require().NoError(s.SetupEmbedEtcd())
+}
+
+func (s *CollectionChurnSuite) TearDownSuite() {
+	paramtable.Get().Reset(paramtable.Get().DataCoordCfg.GCInterval.Key)
+	paramtable.Get().Reset(paramtable.Get().DataCoordCfg.GCDropTolerance.Key)
+
+	s.MiniClusterSuite.TearDownSuite()
+}
+
+func (s *CollectionChurnSuite) TestCreateDropChurn() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	prefixes := integration.CollectionMetaPrefixes()
+	baseline, err := c.CountMetaKeys(ctx, prefixes...)
+	s.NoError(err)
+	log.Info("meta keys before churn", zap.Any("counts", baseline))
+
+	s.NoError(c.ChurnCollections(ctx, 20, 100, 128))
+
+	s.Eventually(func() bool {
+		counts, err := c.CountMetaKeys(ctx, prefixes...)
+		if err != nil {
+			return false
+		}
+		log.Info("meta keys after churn", zap.Any("counts", counts))
+		for _, prefix := range prefixes {
+			if counts[prefix] != baseline[prefix] {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Minute, 2*time.Second)
+}
+
+func TestCollectionChurn(t *testing.T) {
+	suite.Run(t, new(CollectionChurnSuite))
+}
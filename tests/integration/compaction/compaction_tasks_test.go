@@ -0,0 +1,139 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compaction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+// CompactionTasksSuite keeps the default trigger intervals,
+// so that the segments are compacted by manual compaction only.
+type CompactionTasksSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *CompactionTasksSuite) TestListCompactionTasks() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim      = 128
+		dbName   = ""
+		batchNum = 2
+		batch    = 1000
+	)
+	collectionName := "TestListCompactionTasks" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      1,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+
+	for i := 0; i < batchNum; i++ {
+		insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			FieldsData:     []*schemapb.FieldData{integration.NewFloatVectorFieldData(integration.FloatVecField, batch, dim)},
+			HashKeys:       integration.GenerateHashKeys(batch),
+			NumRows:        uint32(batch),
+		})
+		s.NoError(merr.CheckRPCCall(insertResult, err))
+
+		flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+			DbName:          dbName,
+			CollectionNames: []string{collectionName},
+		})
+		s.NoError(merr.CheckRPCCall(flushResp, err))
+		segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+		s.Require().True(has)
+		flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+		s.Require().True(has)
+		s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+	}
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+	compactResp, err := c.Proxy.ManualCompaction(ctx, &milvuspb.ManualCompactionRequest{
+		CollectionID: describeResp.GetCollectionID(),
+	})
+	s.NoError(merr.CheckRPCCall(compactResp, err))
+
+	var plans *milvuspb.GetCompactionPlansResponse
+	s.Eventually(func() bool {
+		plans, err = c.Proxy.GetCompactionStateWithPlans(ctx, &milvuspb.GetCompactionPlansRequest{
+			CompactionID: compactResp.GetCompactionID(),
+		})
+		s.NoError(merr.CheckRPCCall(plans, err))
+		return plans.GetState() == commonpb.CompactionState_Completed
+	}, 2*time.Minute, time.Second)
+	s.Require().NotEmpty(plans.GetMergeInfos())
+
+	// every target of the manual compaction is produced by a finished mix compaction task
+	s.Eventually(func() bool {
+		tasks, err := c.ListCompactionTasks(ctx, collectionName)
+		s.NoError(err)
+		log.Info("compaction tasks", zap.Any("tasks", tasks))
+		return lo.EveryBy(plans.GetMergeInfos(), func(info *milvuspb.CompactionMergeInfo) bool {
+			return lo.ContainsBy(tasks, func(task integration.CompactionTaskInfo) bool {
+				return task.Type == datapb.CompactionType_MixCompaction && task.IsTerminal() &&
+					lo.Contains(task.ResultSegments, info.GetTarget())
+			})
+		})
+	}, time.Minute, time.Second)
+
+	log.Info("TestListCompactionTasks succeed")
+}
+
+func TestCompactionTasks(t *testing.T) {
+	suite.Run(t, new(CompactionTasksSuite))
+}
@@ -0,0 +1,70 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compaction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type FlushCompactionInterleaveSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *FlushCompactionInterleaveSuite) TestNoDataLoss() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+		batch  = 200
+	)
+	collectionName := "TestFlushCompactionNoDataLoss" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       2,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	inserted, err := c.InsertWithFlushAndCompaction(ctx, collectionName, dim, batch,
+		time.Second*30, time.Second*2, time.Second*5)
+	s.NoError(err)
+	s.Positive(inserted)
+
+	log.Info("TestFlushCompactionNoDataLoss succeed", zap.Int64("inserted", inserted))
+}
+
+func TestFlushCompactionInterleave(t *testing.T) {
+	suite.Run(t, new(FlushCompactionInterleaveSuite))
+}
@@ -0,0 +1,135 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compactiongc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type CompactionGCSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *CompactionGCSuite) SetupSuite() {
+	paramtable.Init()
+	// let datacoord remove the binlogs of compacted segments right away
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.GCInterval.Key, "1")
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.GCDropTolerance.Key, "1")
+
+	s.Require().NoError(s.SetupEmbedEtcd())
+}
+
+func (s *CompactionGCSuite) TearDownSuite() {
+	paramtable.Get().Reset(paramtable.Get().DataCoordCfg.GCInterval.Key)
+	paramtable.Get().Reset(paramtable.Get().DataCoordCfg.GCDropTolerance.Key)
+
+	s.MiniClusterSuite.TearDownSuite()
+}
+
+func (s *CompactionGCSuite) TestCompactedSegmentsCollected() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim      = 128
+		dbName   = ""
+		batchNum = 3
+		batch    = 1000
+	)
+	collectionName := "TestCompactedSegmentsCollected" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      1,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+
+	// flush every batch into a small segment of its own
+	for i := 0; i < batchNum; i++ {
+		fVecColumn := integration.NewFloatVectorFieldData(integration.FloatVecField, batch, dim)
+		hashKeys := integration.GenerateHashKeys(batch)
+		insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			FieldsData:     []*schemapb.FieldData{fVecColumn},
+			HashKeys:       hashKeys,
+			NumRows:        uint32(batch),
+		})
+		s.NoError(merr.CheckRPCCall(insertResult, err))
+
+		flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+			DbName:          dbName,
+			CollectionNames: []string{collectionName},
+		})
+		s.NoError(merr.CheckRPCCall(flushResp, err))
+		segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+		s.Require().True(has)
+		flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+		s.Require().True(has)
+		s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+	}
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+
+	s.NoError(c.CompactAndCollectGarbage(ctx, collectionName))
+
+	segments, err := c.MetaWatcher.ShowSegments()
+	s.NoError(err)
+	flushed := lo.Filter(segments, func(segment *datapb.SegmentInfo, _ int) bool {
+		return segment.GetState() == commonpb.SegmentState_Flushed
+	})
+	s.NotEmpty(flushed)
+	s.Equal(int64(batchNum*batch), lo.SumBy(flushed, func(segment *datapb.SegmentInfo) int64 {
+		return segment.GetNumOfRows()
+	}))
+
+	log.Info("TestCompactedSegmentsCollected succeed")
+}
+
+func TestCompactionGC(t *testing.T) {
+	suite.Run(t, new(CompactionGCSuite))
+}
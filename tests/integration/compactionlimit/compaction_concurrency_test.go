@@ -0,0 +1,161 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compactionlimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const concurrency = 2
+
+type CompactionConcurrencySuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *CompactionConcurrencySuite) SetupTest() {
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithCompactionConcurrency(concurrency))
+}
+
+// prepareCollection creates a collection with segmentNum small flushed segments, and returns the collection id.
+func (s *CompactionConcurrencySuite) prepareCollection(ctx context.Context, dim, segmentNum, batch int) int64 {
+	c := s.Cluster
+	collectionName := "TestCompactionConcurrency" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      1,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+
+	for i := 0; i < segmentNum; i++ {
+		insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+			CollectionName: collectionName,
+			FieldsData:     []*schemapb.FieldData{integration.NewFloatVectorFieldData(integration.FloatVecField, batch, dim)},
+			HashKeys:       integration.GenerateHashKeys(batch),
+			NumRows:        uint32(batch),
+		})
+		s.NoError(merr.CheckRPCCall(insertResult, err))
+
+		flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+			CollectionNames: []string{collectionName},
+		})
+		s.NoError(merr.CheckRPCCall(flushResp, err))
+		segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+		s.Require().True(has)
+		flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+		s.Require().True(has)
+		s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, "", collectionName)
+	}
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+	return describeResp.GetCollectionID()
+}
+
+func (s *CompactionConcurrencySuite) TestCompactionConcurrency() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*10)
+	defer cancel()
+
+	const (
+		dim           = 128
+		collectionNum = 3 * concurrency
+		segmentNum    = 3
+		batch         = 1000
+	)
+
+	collectionIDs := make([]int64, 0, collectionNum)
+	for i := 0; i < collectionNum; i++ {
+		collectionIDs = append(collectionIDs, s.prepareCollection(ctx, dim, segmentNum, batch))
+	}
+
+	compactionIDs := make([]int64, 0, collectionNum)
+	for _, collectionID := range collectionIDs {
+		compactResp, err := c.Proxy.ManualCompaction(ctx, &milvuspb.ManualCompactionRequest{
+			CollectionID: collectionID,
+		})
+		s.NoError(merr.CheckRPCCall(compactResp, err))
+		compactionIDs = append(compactionIDs, compactResp.GetCompactionID())
+	}
+
+	completed := func() bool {
+		for _, compactionID := range compactionIDs {
+			state, err := c.Proxy.GetCompactionState(ctx, &milvuspb.GetCompactionStateRequest{
+				CompactionID: compactionID,
+			})
+			s.NoError(merr.CheckRPCCall(state, err))
+			if state.GetState() != commonpb.CompactionState_Completed {
+				return false
+			}
+		}
+		return true
+	}
+
+	var maxExecuting, maxPipelining int
+	for !completed() {
+		counts, err := c.CountCompactionTasks(ctx)
+		s.NoError(err)
+		maxExecuting = max(maxExecuting, counts[datapb.CompactionTaskState_executing])
+		maxPipelining = max(maxPipelining, counts[datapb.CompactionTaskState_pipelining])
+		s.LessOrEqual(counts[datapb.CompactionTaskState_executing], concurrency)
+
+		select {
+		case <-ctx.Done():
+			s.FailNow("waiting for compaction timeout")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	log.Info("compaction concurrency observed", zap.Int("maxExecuting", maxExecuting), zap.Int("maxPipelining", maxPipelining))
+	s.Positive(maxExecuting)
+
+	log.Info("TestCompactionConcurrency succeed")
+}
+
+func TestCompactionConcurrency(t *testing.T) {
+	suite.Run(t, new(CompactionConcurrencySuite))
+}
@@ -0,0 +1,62 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connstorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type ConnectionStormSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *ConnectionStormSuite) TestConnectionStorm() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*3)
+	defer cancel()
+
+	const connNum = 100
+
+	conns, err := c.OpenConnections(connNum)
+	s.Require().NoError(err)
+	s.Len(conns, connNum)
+	integration.CloseConnections(conns)
+
+	// the proxy shall keep serving after the storm
+	states, err := c.MilvusClient.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+	s.NoError(merr.CheckRPCCall(states, err))
+	s.Equal(commonpb.StateCode_Healthy, states.GetState().GetStateCode())
+
+	showResp, err := c.MilvusClient.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{})
+	s.NoError(merr.CheckRPCCall(showResp, err))
+
+	log.Info("TestConnectionStorm succeed")
+}
+
+func TestConnectionStorm(t *testing.T) {
+	suite.Run(t, new(ConnectionStormSuite))
+}
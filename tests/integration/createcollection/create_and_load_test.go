@@ -0,0 +1,77 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package createcollection
+
+import (
+	"context"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+func (s *DuplicateCreateSuite) TestCreateAndLoadCollection() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		shardsNum = 2
+		rowNum    = 1000
+		topk      = 10
+	)
+	collectionName := "TestCreateAndLoadCollection" + funcutil.GenRandomStr()
+
+	collectionID, err := c.CreateAndLoadCollection(ctx, &integration.CreateCollectionConfig{
+		CollectionName:   collectionName,
+		ChannelNum:       shardsNum,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+		IndexType:        integration.IndexHNSW,
+		MetricType:       metric.IP,
+	})
+	s.Require().NoError(err)
+
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collectionName,
+	})
+	s.Require().NoError(merr.CheckRPCCall(describeResp, err))
+	s.Equal(describeResp.GetCollectionID(), collectionID)
+	s.Len(describeResp.GetVirtualChannelNames(), shardsNum)
+
+	loadState, err := c.Proxy.GetLoadState(ctx, &milvuspb.GetLoadStateRequest{
+		CollectionName: collectionName,
+	})
+	s.Require().NoError(merr.CheckRPCCall(loadState, err))
+	s.Equal(commonpb.LoadState_LoadStateLoaded, loadState.GetState())
+
+	// the collection is searchable right away with the index and metric type configured
+	searchReq := integration.ConstructSearchRequest("", collectionName, "", integration.FloatVecField,
+		schemapb.DataType_FloatVector, nil, metric.IP, integration.GetSearchParams(integration.IndexHNSW, metric.IP), 1, dim, topk, -1)
+	searchResult, err := c.Proxy.Search(ctx, searchReq)
+	s.Require().NoError(merr.CheckRPCCall(searchResult, err))
+	s.Len(searchResult.GetResults().GetIds().GetIntId().GetData(), topk)
+
+	log.Info("TestCreateAndLoadCollection succeed")
+}
@@ -0,0 +1,93 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package createcollection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const dim = 128
+
+type DuplicateCreateSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *DuplicateCreateSuite) describeCollectionID(ctx context.Context, collectionName string) int64 {
+	describeResp, err := s.Cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collectionName,
+	})
+	s.Require().NoError(merr.CheckRPCCall(describeResp, err))
+	return describeResp.GetCollectionID()
+}
+
+func (s *DuplicateCreateSuite) TestIdenticalRecreate() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute)
+	defer cancel()
+
+	collectionName := "TestIdenticalRecreate" + funcutil.GenRandomStr()
+	schema := integration.ConstructSchema(collectionName, dim, true)
+
+	existed, err := c.CreateCollectionIdempotent(ctx, "", schema, common.DefaultShardsNum)
+	s.NoError(err)
+	s.False(existed)
+	collectionID := s.describeCollectionID(ctx, collectionName)
+
+	existed, err = c.CreateCollectionIdempotent(ctx, "", schema, common.DefaultShardsNum)
+	s.NoError(err)
+	s.True(existed)
+	s.Equal(collectionID, s.describeCollectionID(ctx, collectionName))
+}
+
+func (s *DuplicateCreateSuite) TestConflictingRecreate() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute)
+	defer cancel()
+
+	collectionName := "TestConflictingRecreate" + funcutil.GenRandomStr()
+	existed, err := c.CreateCollectionIdempotent(ctx, "", integration.ConstructSchema(collectionName, dim, true), common.DefaultShardsNum)
+	s.NoError(err)
+	s.False(existed)
+	collectionID := s.describeCollectionID(ctx, collectionName)
+
+	// different vector dim
+	existed, err = c.CreateCollectionIdempotent(ctx, "", integration.ConstructSchema(collectionName, dim*2, true), common.DefaultShardsNum)
+	s.ErrorIs(err, integration.ErrCollectionConflict)
+	s.True(existed)
+
+	// different shards num
+	existed, err = c.CreateCollectionIdempotent(ctx, "", integration.ConstructSchema(collectionName, dim, true), common.DefaultShardsNum+1)
+	s.ErrorIs(err, integration.ErrCollectionConflict)
+	s.True(existed)
+
+	// the original collection is untouched
+	s.Equal(collectionID, s.describeCollectionID(ctx, collectionName))
+}
+
+func TestDuplicateCreate(t *testing.T) {
+	suite.Run(t, new(DuplicateCreateSuite))
+}
@@ -0,0 +1,61 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package createcollection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+func (s *DuplicateCreateSuite) TestListManyCollections() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*10)
+	defer cancel()
+
+	const collectionNum = 200
+	prefix := "TestListManyCollections" + funcutil.GenRandomStr()
+
+	names := make([]string, 0, collectionNum)
+	for i := 0; i < collectionNum; i++ {
+		name := fmt.Sprintf("%s_%d", prefix, i)
+		marshaledSchema, err := proto.Marshal(integration.ConstructSchema(name, dim, true))
+		s.Require().NoError(err)
+		status, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+			CollectionName: name,
+			Schema:         marshaledSchema,
+			ShardsNum:      common.DefaultShardsNum,
+		})
+		s.Require().NoError(merr.CheckRPCCall(status, err))
+		names = append(names, name)
+	}
+
+	listed, err := c.ListAllCollections(ctx)
+	s.NoError(err)
+	s.ElementsMatch(names, listed)
+
+	log.Info("TestListManyCollections succeed")
+}
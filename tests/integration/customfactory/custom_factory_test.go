@@ -0,0 +1,129 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customfactory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/dependency"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/objectstorage"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+// countingChunkManager counts the writes to the storage.
+type countingChunkManager struct {
+	storage.ChunkManager
+	writes *atomic.Int64
+}
+
+func (cm *countingChunkManager) Write(ctx context.Context, filePath string, content []byte) error {
+	cm.writes.Inc()
+	return cm.ChunkManager.Write(ctx, filePath, content)
+}
+
+func (cm *countingChunkManager) MultiWrite(ctx context.Context, contents map[string][]byte) error {
+	cm.writes.Add(int64(len(contents)))
+	return cm.ChunkManager.MultiWrite(ctx, contents)
+}
+
+// countingFactory hands out countingChunkManager on the local storage of the cluster as the persistent storage.
+type countingFactory struct {
+	dependency.Factory
+	chunkManagers *atomic.Int64
+	writes        *atomic.Int64
+}
+
+func (f *countingFactory) NewPersistentStorageChunkManager(ctx context.Context) (storage.ChunkManager, error) {
+	// the storage config of the cluster is not saved yet when the factory is built, so take it on the fly
+	cm, err := storage.NewChunkManagerFactory("local",
+		objectstorage.RootPath(paramtable.Get().LocalStorageCfg.Path.GetValue())).NewPersistentStorageChunkManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	f.chunkManagers.Inc()
+	return &countingChunkManager{ChunkManager: cm, writes: f.writes}, nil
+}
+
+type CustomFactorySuite struct {
+	integration.MiniClusterSuite
+
+	factory *countingFactory
+}
+
+func (s *CustomFactorySuite) SetupTest() {
+	s.factory = &countingFactory{
+		Factory:       dependency.MockDefaultFactory(true, paramtable.Get()),
+		chunkManagers: atomic.NewInt64(0),
+		writes:        atomic.NewInt64(0),
+	}
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithFactory(s.factory))
+}
+
+func (s *CustomFactorySuite) TestRunOnInjectedFactory() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+	)
+	collectionName := "TestRunOnInjectedFactory" + funcutil.GenRandomStr()
+
+	// ChunkManager of the cluster comes from the injected factory as well
+	s.Positive(s.factory.chunkManagers.Load())
+	s.Equal(paramtable.Get().LocalStorageCfg.Path.GetValue(), c.ChunkManager.RootPath())
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       1,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+	count, err := c.QueryCount(ctx, collectionName, "")
+	s.NoError(err)
+	s.EqualValues(rowNum, count)
+
+	// the binlogs are flushed through the injected factory
+	s.Positive(s.factory.writes.Load())
+
+	log.Info("TestRunOnInjectedFactory succeed")
+}
+
+func (s *CustomFactorySuite) TestNilFactory() {
+	_, err := integration.StartMiniClusterV2(context.Background(), integration.WithFactory(nil))
+	s.ErrorContains(err, "factory of the cluster is nil")
+
+	log.Info("TestNilFactory succeed")
+}
+
+func TestCustomFactory(t *testing.T) {
+	suite.Run(t, new(CustomFactorySuite))
+}
@@ -0,0 +1,133 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datanode
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	grpcdatanode "github.com/milvus-io/milvus/internal/distributed/datanode"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type DataNodeRestartSuite struct {
+	integration.MiniClusterSuite
+
+	// pinnedID is the datanode all channels are pinned to, they wait unassigned while it's gone
+	pinnedID atomic.Int64
+}
+
+func (s *DataNodeRestartSuite) SetupTest() {
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithChannelAssignment(func(channel string, candidates []int64) int64 {
+		if id := s.pinnedID.Load(); lo.Contains(candidates, id) {
+			return id
+		}
+		return integration.KeepUnassigned
+	}))
+}
+
+func (s *DataNodeRestartSuite) getNodeID(ctx context.Context, node *grpcdatanode.Server) int64 {
+	states, err := node.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+	s.Require().NoError(merr.CheckRPCCall(states, err))
+	return states.GetState().GetNodeID()
+}
+
+func (s *DataNodeRestartSuite) TestRestartWithSameChannels() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+	)
+	collectionName := "TestRestartWithSameChannels" + funcutil.GenRandomStr()
+
+	extra, err := c.AddDataNode()
+	s.Require().NoError(err)
+	nodeID := s.getNodeID(ctx, extra)
+	s.pinnedID.Store(nodeID)
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       2,
+		SegmentNum:       1,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+	channels := describeResp.GetVirtualChannelNames()
+	s.Require().Len(channels, 2)
+
+	assignedToPinned := func() bool {
+		assignment, err := c.GetChannelAssignment(ctx)
+		if err != nil {
+			return false
+		}
+		return lo.EveryBy(channels, func(channel string) bool {
+			return assignment[channel] == nodeID
+		})
+	}
+	s.Eventually(assignedToPinned, time.Minute, time.Second)
+
+	s.Require().NoError(c.RestartDataNode(extra))
+	restarted, ok := lo.Find(c.GetAllDataNodes(), func(node *grpcdatanode.Server) bool {
+		return node != c.DataNode && node != extra
+	})
+	s.Require().True(ok)
+	s.Equal(nodeID, s.getNodeID(ctx, restarted))
+	states, err := restarted.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+	s.NoError(merr.CheckRPCCall(states, err))
+	s.Equal(commonpb.StateCode_Healthy, states.GetState().GetStateCode())
+
+	// the restarted datanode rejoins with the same session
+	sessions, err := c.MetaWatcher.ShowSessions()
+	s.NoError(err)
+	s.Len(lo.Filter(sessions, func(session *sessionutil.SessionRaw, _ int) bool {
+		return session.ServerName == typeutil.DataNodeRole && session.ServerID == nodeID
+	}), 1)
+
+	// and watches the same channels again
+	s.Eventually(assignedToPinned, time.Minute, time.Second)
+
+	// the main datanode is not an extra one
+	s.Error(c.RestartDataNode(c.DataNode))
+
+	log.Info("TestRestartWithSameChannels succeed")
+}
+
+func TestDataNodeRestart(t *testing.T) {
+	suite.Run(t, new(DataNodeRestartSuite))
+}
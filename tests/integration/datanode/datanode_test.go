@@ -215,7 +215,8 @@ func (s *DataNodeSuite) insertBatchCollections(prefix string, collectionBatchSiz
 
 func (s *DataNodeSuite) setupData() {
 	// Add the second data node
-	s.Cluster.AddDataNode()
+	_, err := s.Cluster.AddDataNode()
+	s.Require().NoError(err)
 	goRoutineNum := s.maxGoRoutineNum
 	if goRoutineNum > s.numCollections {
 		goRoutineNum = s.numCollections
@@ -269,8 +270,10 @@ func (s *DataNodeSuite) checkQNRestarts(idx int) {
 	// Stop all data nodes
 	s.Cluster.StopAllDataNodes()
 	// Add new data nodes.
-	qn1 := s.Cluster.AddDataNode()
-	qn2 := s.Cluster.AddDataNode()
+	qn1, err := s.Cluster.AddDataNode()
+	s.Require().NoError(err)
+	qn2, err := s.Cluster.AddDataNode()
+	s.Require().NoError(err)
 	time.Sleep(s.waitTimeInSec)
 	cn := fmt.Sprintf("new_collection_r_%d", idx)
 	s.loadCollection(cn)
@@ -291,8 +294,10 @@ func (s *DataNodeSuite) TestSwapQN() {
 	s.setupParam()
 	s.setupData()
 	// Test case with new data nodes added
-	s.Cluster.AddDataNode()
-	s.Cluster.AddDataNode()
+	for i := 0; i < 2; i++ {
+		_, err := s.Cluster.AddDataNode()
+		s.Require().NoError(err)
+	}
 	time.Sleep(s.waitTimeInSec)
 	cn := "new_collection_a"
 	s.loadCollection(cn)
@@ -0,0 +1,93 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embeddedetcd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type EmbeddedEtcdSuite struct {
+	integration.MiniClusterSuite
+
+	dataDir string
+}
+
+// SetupSuite skips the etcd of the suite, the embedded etcd of the cluster is all it needs.
+func (s *EmbeddedEtcdSuite) SetupSuite() {
+	paramtable.Init()
+}
+
+func (s *EmbeddedEtcdSuite) SetupTest() {
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithEmbeddedEtcd())
+	s.dataDir = paramtable.Get().EtcdCfg.DataDir.GetValue()
+}
+
+func (s *EmbeddedEtcdSuite) TearDownTest() {
+	s.MiniClusterSuite.TearDownTest()
+	s.NoDirExists(s.dataDir)
+	s.False(paramtable.Get().EtcdCfg.UseEmbedEtcd.GetAsBool())
+}
+
+func (s *EmbeddedEtcdSuite) TestSelfContainedCluster() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 2000
+	)
+	collectionName := "TestSelfContainedCluster" + funcutil.GenRandomStr()
+
+	s.Require().True(paramtable.Get().EtcdCfg.UseEmbedEtcd.GetAsBool())
+	s.DirExists(s.dataDir)
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       1,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+	count, err := c.QueryCount(ctx, collectionName, "")
+	s.NoError(err)
+	s.EqualValues(rowNum, count)
+
+	// the client of the cluster talks to the embedded etcd holding the meta
+	resp, err := c.EtcdCli.Get(ctx, paramtable.Get().EtcdCfg.RootPath.GetValue(), clientv3.WithPrefix(), clientv3.WithCountOnly())
+	s.Require().NoError(err)
+	s.Positive(resp.Count)
+
+	log.Info("TestSelfContainedCluster succeed")
+}
+
+func TestEmbeddedEtcd(t *testing.T) {
+	suite.Run(t, new(EmbeddedEtcdSuite))
+}
@@ -0,0 +1,108 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdlatency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const etcdLatency = 500 * time.Millisecond
+
+type EtcdLatencySuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *EtcdLatencySuite) SetupTest() {
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithEtcdLatency(etcdLatency))
+}
+
+func (s *EtcdLatencySuite) TestCreateCollectionWithSlowEtcd() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*3)
+	defer cancel()
+
+	collectionName := "TestCreateCollectionWithSlowEtcd" + funcutil.GenRandomStr()
+	schema := integration.ConstructSchema(collectionName, 128, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+
+	start := time.Now()
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	elapsed := time.Since(start)
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+	log.Info("create collection with slow etcd", zap.Duration("elapsed", elapsed))
+	// the collection meta is persisted at least once, so at least one round of latency is paid.
+	s.GreaterOrEqual(elapsed, etcdLatency)
+
+	hasResp, err := c.Proxy.HasCollection(ctx, &milvuspb.HasCollectionRequest{
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(hasResp, err))
+	s.True(hasResp.GetValue())
+}
+
+func (s *EtcdLatencySuite) TestSetEtcdLatency() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*3)
+	defer cancel()
+
+	createCollection := func(collectionName string) time.Duration {
+		schema := integration.ConstructSchema(collectionName, 128, true)
+		marshaledSchema, err := proto.Marshal(schema)
+		s.Require().NoError(err)
+		start := time.Now()
+		createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+			CollectionName: collectionName,
+			Schema:         marshaledSchema,
+			ShardsNum:      common.DefaultShardsNum,
+		})
+		s.Require().NoError(merr.CheckRPCCall(createCollectionStatus, err))
+		return time.Since(start)
+	}
+
+	// etcd recovers
+	s.NoError(c.SetEtcdLatency(0))
+	elapsed := createCollection("TestSetEtcdLatency" + funcutil.GenRandomStr())
+	log.Info("create collection with healthy etcd", zap.Duration("elapsed", elapsed))
+
+	// and degrades again in the middle of the run
+	s.NoError(c.SetEtcdLatency(2 * etcdLatency))
+	elapsed = createCollection("TestSetEtcdLatency" + funcutil.GenRandomStr())
+	log.Info("create collection with degraded etcd", zap.Duration("elapsed", elapsed))
+	s.GreaterOrEqual(elapsed, 2*etcdLatency)
+}
+
+func TestEtcdLatency(t *testing.T) {
+	suite.Run(t, new(EtcdLatencySuite))
+}
@@ -0,0 +1,128 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type ExportSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *ExportSuite) TestExportCollection() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 2500
+	)
+	collectionName := "TestExportCollection" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, false)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	pkColumn := integration.NewInt64FieldData(integration.Int64Field, rowNum)
+	fVecColumn := integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim)
+	insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldsData:     []*schemapb.FieldData{pkColumn, fVecColumn},
+		HashKeys:       integration.GenerateHashKeys(rowNum),
+		NumRows:        uint32(rowNum),
+	})
+	s.NoError(merr.CheckRPCCall(insertResult, err))
+
+	flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+		DbName:          dbName,
+		CollectionNames: []string{collectionName},
+	})
+	s.NoError(merr.CheckRPCCall(flushResp, err))
+	segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+	s.Require().True(has)
+	flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+	s.Require().True(has)
+	s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	// export
+	exportDir := "export-" + collectionName
+	exported, err := c.ExportCollection(ctx, collectionName, exportDir)
+	s.NoError(err)
+	s.Equal(int64(rowNum), exported)
+
+	// check a sampled row of the second page against the inserted data
+	bs, err := c.ChunkManager.Read(ctx, path.Join(c.ChunkManager.RootPath(), exportDir, "1.json"))
+	s.NoError(err)
+	var rows []struct {
+		PK     int64     `json:"int64Field"`
+		Vector []float32 `json:"floatVecField"`
+	}
+	s.NoError(json.Unmarshal(bs, &rows))
+	s.NotEmpty(rows)
+	sampled := rows[len(rows)/2]
+	insertedVectors := fVecColumn.GetVectors().GetFloatVector().GetData()
+	s.Equal(insertedVectors[sampled.PK*dim:(sampled.PK+1)*dim], sampled.Vector)
+
+	log.Info("TestExportCollection succeed")
+}
+
+func TestExport(t *testing.T) {
+	suite.Run(t, new(ExportSuite))
+}
@@ -0,0 +1,171 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filteredsearch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const categoryField = "category"
+
+type ScalarFilterSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *ScalarFilterSuite) run(scalarIndexType string) {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim         = 128
+		dbName      = ""
+		rowNum      = 2000
+		categoryNum = 4
+		nq          = 2
+		topk        = 10
+	)
+	collectionName := "TestScalarFilter" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, true,
+		&schemapb.FieldSchema{FieldID: 100, Name: integration.Int64Field, IsPrimaryKey: true, DataType: schemapb.DataType_Int64, AutoID: true},
+		&schemapb.FieldSchema{FieldID: 101, Name: categoryField, DataType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{
+			FieldID:    102,
+			Name:       integration.FloatVecField,
+			DataType:   schemapb.DataType_FloatVector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: fmt.Sprintf("%d", dim)}},
+		},
+	)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	categories := lo.Map(lo.Range(rowNum), func(i int, _ int) int64 {
+		return int64(i % categoryNum)
+	})
+	insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldsData: []*schemapb.FieldData{
+			{
+				Type:      schemapb.DataType_Int64,
+				FieldName: categoryField,
+				Field: &schemapb.FieldData_Scalars{
+					Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{
+							LongData: &schemapb.LongArray{Data: categories},
+						},
+					},
+				},
+			},
+			integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim),
+		},
+		HashKeys: integration.GenerateHashKeys(rowNum),
+		NumRows:  uint32(rowNum),
+	})
+	s.NoError(merr.CheckRPCCall(insertResult, err))
+
+	flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+		DbName:          dbName,
+		CollectionNames: []string{collectionName},
+	})
+	s.NoError(merr.CheckRPCCall(flushResp, err))
+	segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+	s.Require().True(has)
+	flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+	s.Require().True(has)
+	s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+
+	createIndexStatus, err = c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      categoryField,
+		IndexName:      categoryField,
+		ExtraParams:    integration.ConstructScalarIndexParam(scalarIndexType),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuiltWithIndexName(ctx, collectionName, categoryField, categoryField)
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	const wanted = 2
+	params := integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2)
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, fmt.Sprintf("%s == %d", categoryField, wanted),
+		integration.FloatVecField, schemapb.DataType_FloatVector, []string{categoryField}, metric.L2, params, nq, dim, topk, -1)
+	searchResult, err := c.CheckFilteredSearch(ctx, searchReq)
+	s.Require().NoError(err)
+	s.Len(searchResult.GetResults().GetScores(), nq*topk)
+
+	// the returned category of every hit is the wanted one as well
+	categoryData, ok := lo.Find(searchResult.GetResults().GetFieldsData(), func(fieldData *schemapb.FieldData) bool {
+		return fieldData.GetFieldName() == categoryField
+	})
+	s.Require().True(ok)
+	s.Equal([]int64{wanted}, lo.Uniq(categoryData.GetScalars().GetLongData().GetData()))
+
+	log.Info("TestScalarFilter succeed", zap.String("indexType", scalarIndexType))
+}
+
+func (s *ScalarFilterSuite) TestFilterWithInvertedIndex() {
+	s.run(integration.IndexInverted)
+}
+
+func (s *ScalarFilterSuite) TestFilterWithSTLSortIndex() {
+	s.run(integration.IndexSTLSort)
+}
+
+func TestScalarFilter(t *testing.T) {
+	suite.Run(t, new(ScalarFilterSuite))
+}
@@ -0,0 +1,105 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixedports
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type FixedPortsSuite struct {
+	integration.MiniClusterSuite
+
+	ports map[string]int
+}
+
+func (s *FixedPortsSuite) SetupTest() {
+	s.ports = make(map[string]int)
+	for _, role := range []string{
+		typeutil.RootCoordRole, typeutil.DataCoordRole, typeutil.QueryCoordRole,
+		typeutil.DataNodeRole, typeutil.QueryNodeRole, typeutil.ProxyRole,
+	} {
+		s.ports[role] = s.reservePort()
+	}
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithPorts(s.ports))
+}
+
+// reservePort picks a free port which is not picked yet.
+func (s *FixedPortsSuite) reservePort() int {
+	for {
+		listener, err := net.Listen("tcp", "0.0.0.0:0")
+		s.Require().NoError(err)
+		port := listener.Addr().(*net.TCPAddr).Port
+		s.Require().NoError(listener.Close())
+		if !lo.Contains(lo.Values(s.ports), port) {
+			return port
+		}
+	}
+}
+
+func (s *FixedPortsSuite) TestBindFixedPorts() {
+	params := paramtable.Get()
+	configured := map[string]int{
+		typeutil.RootCoordRole:  params.RootCoordGrpcServerCfg.Port.GetAsInt(),
+		typeutil.DataCoordRole:  params.DataCoordGrpcServerCfg.Port.GetAsInt(),
+		typeutil.QueryCoordRole: params.QueryCoordGrpcServerCfg.Port.GetAsInt(),
+		typeutil.DataNodeRole:   params.DataNodeGrpcServerCfg.Port.GetAsInt(),
+		typeutil.QueryNodeRole:  params.QueryNodeGrpcServerCfg.Port.GetAsInt(),
+		typeutil.ProxyRole:      params.ProxyGrpcServerCfg.Port.GetAsInt(),
+	}
+	s.Equal(s.ports, configured)
+
+	for role, port := range s.ports {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), time.Second)
+		s.NoError(err, "%s is not serving on port %d", role, port)
+		if err == nil {
+			conn.Close()
+		}
+	}
+
+	log.Info("TestBindFixedPorts succeed")
+}
+
+func (s *FixedPortsSuite) TestPortInUse() {
+	// the ports of the running cluster are occupied
+	_, err := integration.StartMiniClusterV2(context.Background(), integration.WithPorts(map[string]int{
+		typeutil.ProxyRole: s.ports[typeutil.ProxyRole],
+	}))
+	s.ErrorContains(err, "is not free")
+
+	_, err = integration.StartMiniClusterV2(context.Background(), integration.WithPorts(map[string]int{
+		typeutil.IndexNodeRole: s.reservePort(),
+	}))
+	s.ErrorContains(err, "is not supported")
+
+	log.Info("TestPortInUse succeed")
+}
+
+func TestFixedPorts(t *testing.T) {
+	suite.Run(t, new(FixedPortsSuite))
+}
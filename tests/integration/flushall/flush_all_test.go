@@ -0,0 +1,101 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flushall
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type FlushAllSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *FlushAllSuite) TestWaitForAllFlushed() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim           = 128
+		rowNum        = 1000
+		collectionNum = 3
+	)
+	prefix := "TestWaitForAllFlushed" + funcutil.GenRandomStr()
+
+	collections := make([]string, 0, collectionNum)
+	for i := 0; i < collectionNum; i++ {
+		collectionName := fmt.Sprintf("%s_%d", prefix, i)
+		schema := integration.ConstructSchema(collectionName, dim, true)
+		marshaledSchema, err := proto.Marshal(schema)
+		s.NoError(err)
+		createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+			CollectionName: collectionName,
+			Schema:         marshaledSchema,
+			ShardsNum:      common.DefaultShardsNum,
+		})
+		s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+		insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+			CollectionName: collectionName,
+			FieldsData:     []*schemapb.FieldData{integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim)},
+			HashKeys:       integration.GenerateHashKeys(rowNum),
+			NumRows:        uint32(rowNum),
+		})
+		s.NoError(merr.CheckRPCCall(insertResult, err))
+		collections = append(collections, collectionName)
+	}
+
+	flushAllResp, err := c.Proxy.FlushAll(ctx, &milvuspb.FlushAllRequest{})
+	s.NoError(merr.CheckRPCCall(flushAllResp, err))
+	s.NoError(c.WaitForAllFlushed(ctx, collections))
+
+	for _, collectionName := range collections {
+		infoResp, err := c.Proxy.GetPersistentSegmentInfo(ctx, &milvuspb.GetPersistentSegmentInfoRequest{
+			CollectionName: collectionName,
+		})
+		s.NoError(merr.CheckRPCCall(infoResp, err))
+		flushed := lo.Filter(infoResp.GetInfos(), func(info *milvuspb.PersistentSegmentInfo, _ int) bool {
+			return info.GetState() == commonpb.SegmentState_Flushed
+		})
+		s.NotEmpty(flushed, "collection %s has no flushed segment", collectionName)
+		s.Equal(int64(rowNum), lo.SumBy(flushed, func(info *milvuspb.PersistentSegmentInfo) int64 {
+			return info.GetNumRows()
+		}))
+	}
+
+	log.Info("TestWaitForAllFlushed succeed")
+}
+
+func TestFlushAll(t *testing.T) {
+	suite.Run(t, new(FlushAllSuite))
+}
@@ -0,0 +1,73 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flushall
+
+import (
+	"context"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+func (s *FlushAllSuite) TestFlushAndWait() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim       = 128
+		dbName    = ""
+		rowNum    = 1000
+		shardsNum = 2
+	)
+	collectionName := "TestFlushAndWait" + funcutil.GenRandomStr()
+
+	s.Require().NoError(c.CreateAutoIDCollection(ctx, dbName, collectionName, dim, shardsNum))
+	_, err := c.InsertAutoID(ctx, dbName, collectionName, rowNum, dim)
+	s.Require().NoError(err)
+
+	segmentIDs, err := c.FlushAndWait(ctx, collectionName)
+	s.Require().NoError(err)
+	s.Len(segmentIDs, shardsNum)
+
+	// the segments are flushed as soon as it returns
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collectionName,
+	})
+	s.Require().NoError(merr.CheckRPCCall(describeResp, err))
+	segments, err := c.MetaWatcher.ListSegments(describeResp.GetCollectionID())
+	s.NoError(err)
+	flushed := lo.Filter(segments, func(segment *datapb.SegmentInfo, _ int) bool {
+		return lo.Contains(segmentIDs, segment.GetID())
+	})
+	s.Len(flushed, shardsNum)
+	for _, segment := range flushed {
+		s.Equal(commonpb.SegmentState_Flushed, segment.GetState())
+	}
+	s.EqualValues(rowNum, lo.SumBy(flushed, func(segment *datapb.SegmentInfo) int64 {
+		return segment.GetNumOfRows()
+	}))
+
+	log.Info("TestFlushAndWait succeed")
+}
@@ -0,0 +1,148 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupby
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const categoryField = "category"
+
+type GroupSizeSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *GroupSizeSuite) TestSearchGroupBySize() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim         = 128
+		dbName      = ""
+		rowNum      = 2000
+		categoryNum = 10
+		nq          = 2
+		topk        = 5
+		groupSize   = 3
+	)
+	collectionName := "TestSearchGroupBySize" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, true,
+		&schemapb.FieldSchema{FieldID: 100, Name: integration.Int64Field, IsPrimaryKey: true, DataType: schemapb.DataType_Int64, AutoID: true},
+		&schemapb.FieldSchema{FieldID: 101, Name: categoryField, DataType: schemapb.DataType_Int64},
+		&schemapb.FieldSchema{
+			FieldID:    102,
+			Name:       integration.FloatVecField,
+			DataType:   schemapb.DataType_FloatVector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: fmt.Sprintf("%d", dim)}},
+		},
+	)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	categories := lo.Map(lo.Range(rowNum), func(i int, _ int) int64 {
+		return int64(i % categoryNum)
+	})
+	insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldsData: []*schemapb.FieldData{
+			{
+				Type:      schemapb.DataType_Int64,
+				FieldName: categoryField,
+				Field: &schemapb.FieldData_Scalars{
+					Scalars: &schemapb.ScalarField{
+						Data: &schemapb.ScalarField_LongData{
+							LongData: &schemapb.LongArray{Data: categories},
+						},
+					},
+				},
+			},
+			integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim),
+		},
+		HashKeys: integration.GenerateHashKeys(rowNum),
+		NumRows:  uint32(rowNum),
+	})
+	s.NoError(merr.CheckRPCCall(insertResult, err))
+
+	flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+		DbName:          dbName,
+		CollectionNames: []string{collectionName},
+	})
+	s.NoError(merr.CheckRPCCall(flushResp, err))
+	segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+	s.Require().True(has)
+	flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+	s.Require().True(has)
+	s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexHNSW, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	params := integration.GetSearchParams(integration.IndexHNSW, metric.L2)
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, "",
+		integration.FloatVecField, schemapb.DataType_FloatVector, nil, metric.L2, params, nq, dim, topk, -1)
+	searchResult, err := c.SearchGroupBySize(ctx, searchReq, categoryField, groupSize)
+	s.Require().NoError(err)
+	// there are far more rows than topk * groupSize in every category
+	s.LessOrEqual(len(searchResult.GetResults().GetScores()), nq*topk*groupSize)
+	s.Greater(len(searchResult.GetResults().GetScores()), nq*topk)
+
+	log.Info("TestSearchGroupBySize succeed")
+}
+
+func TestGroupSize(t *testing.T) {
+	suite.Run(t, new(GroupSizeSuite))
+}
@@ -0,0 +1,84 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthall
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type HealthAllSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *HealthAllSuite) waitHealthy(ctx context.Context) map[string]string {
+	var health map[string]string
+	s.Eventually(func() bool {
+		healthy, states, err := s.Cluster.CheckHealthAll(ctx)
+		if err != nil {
+			return false
+		}
+		health = states
+		if !healthy {
+			log.Info("cluster not healthy yet", zap.Any("health", states))
+		}
+		return healthy
+	}, time.Minute, time.Second)
+	return health
+}
+
+func (s *HealthAllSuite) TestTopologyChanges() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	s.waitHealthy(ctx)
+
+	// the extra nodes are checked as well
+	_, err := c.AddQueryNode()
+	s.Require().NoError(err)
+	_, err = c.AddDataNode()
+	s.Require().NoError(err)
+	health := s.waitHealthy(ctx)
+	s.Equal(commonpb.StateCode_Healthy.String(), health["extra queryNode 0"])
+	s.Equal(commonpb.StateCode_Healthy.String(), health["extra dataNode 0"])
+
+	// a stopped coordinator is reported as unavailable instead of panicking
+	c.StopRootCoord()
+	healthy, health, err := c.CheckHealthAll(ctx)
+	s.Require().NoError(err)
+	s.False(healthy)
+	s.Equal(integration.HealthUnavailable, health["rootCoord"])
+
+	c.StartRootCoord()
+	health = s.waitHealthy(ctx)
+	s.Equal(commonpb.StateCode_Healthy.String(), health["rootCoord"])
+
+	log.Info("TestTopologyChanges succeed")
+}
+
+func TestHealthAll(t *testing.T) {
+	suite.Run(t, new(HealthAllSuite))
+}
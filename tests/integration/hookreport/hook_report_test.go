@@ -0,0 +1,77 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hookreport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/internal/util/hookutil"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const reportBuffer = 100
+
+type HookReportSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *HookReportSuite) SetupTest() {
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithReportBuffer(reportBuffer))
+}
+
+func (s *HookReportSuite) TestCountInsertReports() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim     = 128
+		dbName  = ""
+		rowNum  = 100
+		inserts = 3
+	)
+	collectionName := "TestCountInsertReports" + funcutil.GenRandomStr()
+
+	s.Require().NoError(c.CreateAutoIDCollection(ctx, dbName, collectionName, dim, 1))
+	for i := 0; i < inserts; i++ {
+		_, err := c.InsertAutoID(ctx, dbName, collectionName, rowNum, dim)
+		s.Require().NoError(err)
+	}
+
+	// every insert is reported even though no one was receiving
+	isInsert := integration.ReportOfOpType(hookutil.OpTypeInsert)
+	count := 0
+	for len(c.Extension.GetReportChan()) > 0 {
+		if isInsert(<-c.Extension.GetReportChan()) {
+			count++
+		}
+	}
+	s.Equal(inserts, count)
+	s.Zero(c.Extension.Drained())
+
+	log.Info("TestCountInsertReports succeed")
+}
+
+func TestHookReport(t *testing.T) {
+	suite.Run(t, new(HookReportSuite))
+}
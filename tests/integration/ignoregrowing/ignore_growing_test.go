@@ -0,0 +1,139 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ignoregrowing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type IgnoreGrowingSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *IgnoreGrowingSuite) TestSearchIgnoreGrowing() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim       = 128
+		dbName    = ""
+		sealedNum = 1000
+		growNum   = 1000
+		topk      = 10
+	)
+	collectionName := "TestSearchIgnoreGrowing" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, false)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	insert := func(start int64, rowNum int) {
+		insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			FieldsData: []*schemapb.FieldData{
+				integration.NewInt64FieldDataWithStart(integration.Int64Field, rowNum, start),
+				integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim),
+			},
+			HashKeys: integration.GenerateHashKeys(rowNum),
+			NumRows:  uint32(rowNum),
+		})
+		s.NoError(merr.CheckRPCCall(insertResult, err))
+	}
+
+	// sealed rows with pk in [0, sealedNum)
+	insert(0, sealedNum)
+	flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+		DbName:          dbName,
+		CollectionNames: []string{collectionName},
+	})
+	s.NoError(merr.CheckRPCCall(flushResp, err))
+	segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+	s.Require().True(has)
+	flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+	s.Require().True(has)
+	s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	// growing rows with pk in [sealedNum, sealedNum+growNum), not flushed
+	insert(sealedNum, growNum)
+
+	// only the growing rows match the filter
+	expr := fmt.Sprintf("%s >= %d", integration.Int64Field, sealedNum)
+	search := func(ignoreGrowing bool) []int64 {
+		params := integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2)
+		searchReq := integration.ConstructSearchRequestWithConsistencyLevel(dbName, collectionName, expr,
+			integration.FloatVecField, schemapb.DataType_FloatVector, nil, metric.L2, params, 1, dim, topk, -1,
+			false, commonpb.ConsistencyLevel_Strong)
+		searchResult, err := c.Proxy.Search(ctx, integration.WithIgnoreGrowing(searchReq, ignoreGrowing))
+		s.NoError(merr.CheckRPCCall(searchResult, err))
+		return searchResult.GetResults().GetIds().GetIntId().GetData()
+	}
+
+	s.Empty(search(true))
+
+	ids := search(false)
+	s.Len(ids, topk)
+	for _, id := range ids {
+		s.GreaterOrEqual(id, int64(sealedNum))
+	}
+
+	log.Info("TestSearchIgnoreGrowing succeed")
+}
+
+func TestIgnoreGrowing(t *testing.T) {
+	suite.Run(t, new(IgnoreGrowingSuite))
+}
@@ -0,0 +1,109 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexstat
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+func (s *GetIndexStatisticsSuite) TestPendingIndexRows() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 3000
+	)
+	collectionName := "TestPendingIndexRows" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      1,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	insertAndFlush := func() {
+		insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			FieldsData:     []*schemapb.FieldData{integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim)},
+			HashKeys:       integration.GenerateHashKeys(rowNum),
+			NumRows:        uint32(rowNum),
+		})
+		s.NoError(merr.CheckRPCCall(insertResult, err))
+		flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+			DbName:          dbName,
+			CollectionNames: []string{collectionName},
+		})
+		s.NoError(merr.CheckRPCCall(flushResp, err))
+		segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+		s.Require().True(has)
+		flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+		s.Require().True(has)
+		s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+	}
+
+	insertAndFlush()
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+
+	stats, err := c.GetIndexStats(ctx, collectionName, integration.FloatVecField)
+	s.NoError(err)
+	s.NoError(integration.CheckIndexStats(stats))
+	s.Equal(int64(rowNum), stats.IndexedRows)
+	s.Zero(stats.PendingIndexRows)
+
+	// the rows flushed after the index is built are pending until their segments are indexed
+	insertAndFlush()
+	s.Eventually(func() bool {
+		stats, err := c.GetIndexStats(ctx, collectionName, integration.FloatVecField)
+		s.NoError(err)
+		s.NoError(integration.CheckIndexStats(stats))
+		s.Equal(int64(2*rowNum), stats.TotalRows)
+		s.GreaterOrEqual(stats.IndexedRows, int64(rowNum))
+		s.LessOrEqual(stats.PendingIndexRows, int64(rowNum))
+		log.Info("index stats", zap.Any("stats", stats))
+		return stats.PendingIndexRows == 0
+	}, time.Minute, 200*time.Millisecond)
+
+	log.Info("TestPendingIndexRows succeed")
+}
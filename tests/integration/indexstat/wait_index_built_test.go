@@ -0,0 +1,107 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexstat
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+func (s *GetIndexStatisticsSuite) TestWaitForIndexBuilt() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 3000
+	)
+	collectionName := "TestWaitForIndexBuilt" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, true,
+		&schemapb.FieldSchema{Name: integration.Int64Field, IsPrimaryKey: true, DataType: schemapb.DataType_Int64, AutoID: true},
+		&schemapb.FieldSchema{Name: integration.DoubleField, DataType: schemapb.DataType_Double},
+		&schemapb.FieldSchema{
+			Name:       integration.FloatVecField,
+			DataType:   schemapb.DataType_FloatVector,
+			TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: fmt.Sprint(dim)}},
+		},
+	)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      1,
+	})
+	s.Require().NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	insertAndFlush := func(seed int64) {
+		_, err := c.InsertRandomData(ctx, collectionName, rowNum, seed)
+		s.Require().NoError(err)
+		_, err = c.FlushAndWait(ctx, collectionName)
+		s.Require().NoError(err)
+	}
+	insertAndFlush(1)
+
+	// fields without index are reported at once
+	s.Error(c.WaitForIndexBuilt(ctx, dbName, collectionName, integration.FloatVecField))
+
+	for field, params := range map[string][]*commonpb.KeyValuePair{
+		integration.FloatVecField: integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+		integration.DoubleField:   integration.ConstructScalarIndexParam(integration.IndexInverted),
+	} {
+		createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+			CollectionName: collectionName,
+			FieldName:      field,
+			IndexName:      field + "_index",
+			ExtraParams:    params,
+		})
+		s.Require().NoError(merr.CheckRPCCall(createIndexStatus, err))
+	}
+	for _, field := range []string{integration.FloatVecField, integration.DoubleField} {
+		s.NoError(c.WaitForIndexBuilt(ctx, dbName, collectionName, field))
+		stats, err := c.GetIndexStats(ctx, collectionName, field)
+		s.NoError(err)
+		s.Equal(int64(rowNum), stats.IndexedRows)
+	}
+	s.Error(c.WaitForIndexBuilt(ctx, dbName, collectionName, integration.Int64Field))
+
+	// the rows flushed after the index is built are waited for as well
+	insertAndFlush(2)
+	s.NoError(c.WaitForIndexBuilt(ctx, dbName, collectionName, integration.FloatVecField))
+	stats, err := c.GetIndexStats(ctx, collectionName, integration.FloatVecField)
+	s.NoError(err)
+	s.Equal(int64(2*rowNum), stats.IndexedRows)
+
+	log.Info("TestWaitForIndexBuilt succeed")
+}
@@ -121,6 +121,42 @@ func (s *InsertSuite) TestInsert() {
 	log.Info("==================")
 }
 
+// insert into a nonexistent or dropped collection should fail with collection not found
+func (s *InsertSuite) TestInsertIntoNonExistentCollection() {
+	c := s.Cluster
+	ctx, cancel := context.WithCancel(c.GetContext())
+	defer cancel()
+
+	const (
+		dbName = ""
+		dim    = 128
+		rowNum = 100
+	)
+
+	err := s.InsertAndFlush(ctx, dbName, "TestInsertNonExistent"+funcutil.GenRandomStr(), rowNum, dim)
+	s.ErrorIs(err, merr.ErrCollectionNotFound)
+
+	collectionName := "TestInsertDropped" + funcutil.GenRandomStr()
+	schema := integration.ConstructSchema(collectionName, dim, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+	dropCollectionStatus, err := c.Proxy.DropCollection(ctx, &milvuspb.DropCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(dropCollectionStatus, err))
+
+	err = s.InsertAndFlush(ctx, dbName, collectionName, rowNum, dim)
+	s.ErrorIs(err, merr.ErrCollectionNotFound)
+}
+
 func TestInsert(t *testing.T) {
 	suite.Run(t, new(InsertSuite))
 }
@@ -0,0 +1,125 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package insert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+func (s *InsertSuite) TestInsertRandomData() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+		seed   = 42
+	)
+
+	createCollection := func(collectionName string) {
+		schema := integration.ConstructSchema(collectionName, dim, false,
+			&schemapb.FieldSchema{
+				Name:         integration.VarCharField,
+				IsPrimaryKey: true,
+				DataType:     schemapb.DataType_VarChar,
+				TypeParams:   []*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "64"}},
+			},
+			&schemapb.FieldSchema{Name: integration.Int64Field, DataType: schemapb.DataType_Int64},
+			&schemapb.FieldSchema{Name: integration.FloatField, DataType: schemapb.DataType_Float},
+			&schemapb.FieldSchema{
+				Name:       integration.FloatVecField,
+				DataType:   schemapb.DataType_FloatVector,
+				TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: fmt.Sprint(dim)}},
+			},
+		)
+		marshaledSchema, err := proto.Marshal(schema)
+		s.Require().NoError(err)
+		createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			Schema:         marshaledSchema,
+			ShardsNum:      common.DefaultShardsNum,
+		})
+		s.Require().NoError(merr.CheckRPCCall(createCollectionStatus, err))
+	}
+	collectionName := "TestInsertRandomData" + funcutil.GenRandomStr()
+	otherCollectionName := "TestInsertRandomData" + funcutil.GenRandomStr()
+	createCollection(collectionName)
+	createCollection(otherCollectionName)
+
+	// the same seed generates the same rows
+	result, err := c.InsertRandomData(ctx, collectionName, rowNum, seed)
+	s.Require().NoError(err)
+	pks := result.GetIDs().GetStrId().GetData()
+	s.Len(pks, rowNum)
+	result, err = c.InsertRandomData(ctx, otherCollectionName, rowNum, seed)
+	s.Require().NoError(err)
+	s.Equal(pks, result.GetIDs().GetStrId().GetData())
+	result, err = c.InsertRandomData(ctx, otherCollectionName, rowNum, seed+1)
+	s.Require().NoError(err)
+	s.NotEqual(pks, result.GetIDs().GetStrId().GetData())
+
+	// and the returned primary keys can be queried back
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.Require().NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.Require().NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	queried := pks[:10]
+	queryResp, err := c.Proxy.Query(ctx, &milvuspb.QueryRequest{
+		DbName:           dbName,
+		CollectionName:   collectionName,
+		Expr:             fmt.Sprintf(`%s in ["%s"]`, integration.VarCharField, strings.Join(queried, `","`)),
+		OutputFields:     []string{integration.VarCharField},
+		ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+	})
+	s.Require().NoError(merr.CheckRPCCall(queryResp, err))
+	for _, fieldData := range queryResp.GetFieldsData() {
+		if fieldData.GetFieldName() == integration.VarCharField {
+			s.ElementsMatch(queried, fieldData.GetScalars().GetStringData().GetData())
+		}
+	}
+
+	log.Info("TestInsertRandomData succeed")
+}
@@ -0,0 +1,43 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+)
+
+func (s *ClientInterceptorSuite) TestRawHealthClient() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute)
+	defer cancel()
+
+	conn, err := c.GetClientConn()
+	s.Require().NoError(err)
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	s.Require().NoError(err)
+	s.Equal(grpc_health_v1.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	// stubs over the connection go through the interceptors of the cluster too
+	s.Equal(1, s.counter.count(grpc_health_v1.Health_Check_FullMethodName))
+
+	log.Info("TestRawHealthClient succeed")
+}
@@ -0,0 +1,86 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+// callCounter counts the unary calls by method.
+type callCounter struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (c *callCounter) intercept(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	c.mu.Lock()
+	c.calls[method]++
+	c.mu.Unlock()
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func (c *callCounter) count(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[method]
+}
+
+type ClientInterceptorSuite struct {
+	integration.MiniClusterSuite
+
+	counter *callCounter
+}
+
+func (s *ClientInterceptorSuite) SetupTest() {
+	s.counter = &callCounter{calls: make(map[string]int)}
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithClientInterceptors(
+		[]grpc.UnaryClientInterceptor{s.counter.intercept}, nil))
+}
+
+func (s *ClientInterceptorSuite) TestCountMilvusClientCalls() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute)
+	defer cancel()
+
+	const calls = 10
+	for i := 0; i < calls; i++ {
+		resp, err := c.MilvusClient.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{})
+		s.NoError(merr.CheckRPCCall(resp, err))
+	}
+	states, err := c.MilvusClient.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+	s.NoError(merr.CheckRPCCall(states, err))
+
+	s.Equal(calls, s.counter.count(milvuspb.MilvusService_ShowCollections_FullMethodName))
+	s.Equal(1, s.counter.count(milvuspb.MilvusService_GetComponentStates_FullMethodName))
+
+	log.Info("TestCountMilvusClientCalls succeed")
+}
+
+func TestClientInterceptor(t *testing.T) {
+	suite.Run(t, new(ClientInterceptorSuite))
+}
@@ -0,0 +1,71 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type DialOptionsSuite struct {
+	integration.MiniClusterSuite
+
+	attempts *callCounter
+}
+
+func (s *DialOptionsSuite) SetupTest() {
+	s.attempts = &callCounter{calls: make(map[string]int)}
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithClientDialOptions(
+		grpc.WithDefaultCallOptions(grpc_retry.Disable()),
+		grpc.WithChainUnaryInterceptor(s.attempts.intercept),
+	))
+}
+
+func (s *DialOptionsSuite) TestRetryDisabled() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute)
+	defer cancel()
+
+	resp, err := c.MilvusClient.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{})
+	s.Require().NoError(merr.CheckRPCCall(resp, err))
+	s.Equal(1, s.attempts.count(milvuspb.MilvusService_ShowCollections_FullMethodName))
+
+	// with proxy gone the call fails as unavailable on the first attempt instead of being retried
+	c.StopProxy()
+	defer c.StartProxy()
+	_, err = c.MilvusClient.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{})
+	s.Equal(codes.Unavailable, status.Code(err))
+	s.Equal(2, s.attempts.count(milvuspb.MilvusService_ShowCollections_FullMethodName))
+
+	log.Info("TestRetryDisabled succeed")
+}
+
+func TestDialOptions(t *testing.T) {
+	suite.Run(t, new(DialOptionsSuite))
+}
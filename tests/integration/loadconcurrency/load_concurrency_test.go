@@ -0,0 +1,103 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadconcurrency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const (
+	dim           = 128
+	segmentNum    = 8
+	segmentRowNum = 2000
+)
+
+// LoadConcurrencySuite loads the same multi-segment collection under different querynode load concurrency.
+// The load time of each run is only logged, the high concurrency run is usually faster
+// but that depends on the cores of the machine, so it's not asserted.
+type LoadConcurrencySuite struct {
+	integration.MiniClusterSuite
+
+	loadTime map[int]time.Duration
+}
+
+func (s *LoadConcurrencySuite) SetupSuite() {
+	s.loadTime = make(map[int]time.Duration)
+	s.MiniClusterSuite.SetupSuite()
+}
+
+func (s *LoadConcurrencySuite) TearDownSuite() {
+	log.Info("load time under different concurrency", zap.Any("loadTime", s.loadTime))
+	s.MiniClusterSuite.TearDownSuite()
+}
+
+// SetupTest does nothing, the cluster is started by each test with its own load concurrency.
+func (s *LoadConcurrencySuite) SetupTest() {}
+
+func (s *LoadConcurrencySuite) loadWithConcurrency(concurrency int) {
+	s.SetupTestWithOptions(integration.WithQueryNodeLoadConcurrency(concurrency))
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	collectionName := "TestLoadConcurrency" + funcutil.GenRandomStr()
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       segmentNum,
+		RowNumPerSegment: segmentRowNum,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+
+	elapsed := s.LoadCollectionWithTiming(ctx, "", collectionName)
+	s.loadTime[concurrency] = elapsed
+	log.Info("collection loaded", zap.Int("concurrency", concurrency), zap.Duration("elapsed", elapsed))
+
+	queryResult, err := c.Proxy.Query(ctx, &milvuspb.QueryRequest{
+		CollectionName:   collectionName,
+		OutputFields:     []string{"count(*)"},
+		ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+	})
+	s.NoError(merr.CheckRPCCall(queryResult, err))
+	s.Require().Len(queryResult.GetFieldsData(), 1)
+	s.Equal([]int64{segmentNum * segmentRowNum}, queryResult.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+}
+
+func (s *LoadConcurrencySuite) TestLoadWithLowConcurrency() {
+	s.loadWithConcurrency(1)
+}
+
+func (s *LoadConcurrencySuite) TestLoadWithHighConcurrency() {
+	s.loadWithConcurrency(16)
+}
+
+func TestLoadConcurrency(t *testing.T) {
+	suite.Run(t, new(LoadConcurrencySuite))
+}
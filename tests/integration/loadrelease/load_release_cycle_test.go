@@ -0,0 +1,69 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadrelease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type LoadReleaseCycleSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *LoadReleaseCycleSuite) TestRepeatedLoadRelease() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*10)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 2000
+		cycles = 20
+	)
+	collectionName := "TestRepeatedLoadRelease" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       2,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(c.LoadReleaseCycle(ctx, collectionName, cycles))
+
+	// the collection is still loadable and complete after all the cycles
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+	count, err := c.QueryCount(ctx, collectionName, "")
+	s.NoError(err)
+	s.EqualValues(2*rowNum, count)
+
+	log.Info("TestRepeatedLoadRelease succeed")
+}
+
+func TestLoadReleaseCycle(t *testing.T) {
+	suite.Run(t, new(LoadReleaseCycleSuite))
+}
@@ -0,0 +1,83 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadrelease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type LoadReleaseRaceSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *LoadReleaseRaceSuite) TestRaceLoadRelease() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rounds = 10
+	)
+	collectionName := "TestRaceLoadRelease" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       2,
+		SegmentNum:       2,
+		RowNumPerSegment: 2000,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+
+	s.NoError(c.RaceLoadRelease(ctx, dbName, collectionName, rounds, 30*time.Second))
+
+	state, err := c.VerifyLoadState(ctx, dbName, collectionName)
+	s.NoError(err)
+	s.Contains([]commonpb.LoadState{commonpb.LoadState_LoadStateLoaded, commonpb.LoadState_LoadStateNotLoad}, state)
+
+	// the collection shall still be loadable and queryable after the race
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	state, err = c.VerifyLoadState(ctx, dbName, collectionName)
+	s.NoError(err)
+	s.Equal(commonpb.LoadState_LoadStateLoaded, state)
+
+	log.Info("TestRaceLoadRelease succeed")
+}
+
+func TestLoadReleaseRace(t *testing.T) {
+	suite.Run(t, new(LoadReleaseRaceSuite))
+}
@@ -0,0 +1,86 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadrelease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type LoadStateSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *LoadStateSuite) SetupTest() {
+	// load segments one by one so that the loading lasts long enough to be observed
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithQueryNodeLoadConcurrency(1))
+}
+
+func (s *LoadStateSuite) TestLoadStateWhileLoading() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 3000
+	)
+	collectionName := "TestLoadStateWhileLoading" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       2,
+		SegmentNum:       10,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+	state, err := c.VerifyLoadState(ctx, dbName, collectionName)
+	s.NoError(err)
+	s.Equal(commonpb.LoadState_LoadStateNotLoad, state)
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	sawLoading, err := c.TrackLoadState(ctx, dbName, collectionName)
+	s.NoError(err)
+	s.True(sawLoading)
+
+	state, err = c.VerifyLoadState(ctx, dbName, collectionName)
+	s.NoError(err)
+	s.Equal(commonpb.LoadState_LoadStateLoaded, state)
+
+	log.Info("TestLoadStateWhileLoading succeed")
+}
+
+func TestLoadState(t *testing.T) {
+	suite.Run(t, new(LoadStateSuite))
+}
@@ -0,0 +1,78 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadrelease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type QueryCoordRestartSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *QueryCoordRestartSuite) TestSearchAfterQueryCoordRestart() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 2000
+		nq     = 1
+		topk   = 10
+	)
+	collectionName := "TestSearchAfterQueryCoordRestart" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+
+	params := integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2)
+	searchReq := integration.ConstructSearchRequestWithConsistencyLevel(dbName, collectionName, "",
+		integration.FloatVecField, schemapb.DataType_FloatVector, nil, metric.L2, params, nq, dim, topk, -1,
+		false, commonpb.ConsistencyLevel_Bounded)
+	s.Require().NoError(c.RestartQueryCoordWithLoaded(ctx, collectionName, searchReq))
+
+	searchResult, err := c.Proxy.Search(ctx, searchReq)
+	s.NoError(merr.CheckRPCCall(searchResult, err))
+	s.Len(searchResult.GetResults().GetScores(), nq*topk)
+
+	log.Info("TestSearchAfterQueryCoordRestart succeed")
+}
+
+func TestQueryCoordRestart(t *testing.T) {
+	suite.Run(t, new(QueryCoordRestartSuite))
+}
@@ -0,0 +1,111 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadrelease
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type RefreshLoadSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *RefreshLoadSuite) TestRefreshLoad() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 2000
+	)
+	collectionName := "TestRefreshLoad" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       1,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	// more data flushed after the collection is loaded
+	insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldsData:     []*schemapb.FieldData{integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim)},
+		HashKeys:       integration.GenerateHashKeys(rowNum),
+		NumRows:        rowNum,
+	})
+	s.NoError(merr.CheckRPCCall(insertResult, err))
+	flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+		DbName:          dbName,
+		CollectionNames: []string{collectionName},
+	})
+	s.NoError(merr.CheckRPCCall(flushResp, err))
+	segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+	s.Require().True(has)
+	flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+	s.Require().True(has)
+	s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+
+	s.Require().NoError(c.RefreshLoad(ctx, collectionName))
+	s.NoError(c.CheckSegmentsLoaded(ctx, collectionName, segmentIDs.GetData()))
+
+	// the new rows are served from the refreshed segments
+	newIDs := insertResult.GetIDs().GetIntId().GetData()[:10]
+	queryResult, err := c.Proxy.Query(ctx, &milvuspb.QueryRequest{
+		DbName:           dbName,
+		CollectionName:   collectionName,
+		Expr:             fmt.Sprintf("%s in [%s]", integration.Int64Field, strings.Join(lo.Map(newIDs, func(pk int64, _ int) string { return strconv.FormatInt(pk, 10) }), ",")),
+		OutputFields:     []string{integration.Int64Field},
+		ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+	})
+	s.NoError(merr.CheckRPCCall(queryResult, err))
+	s.ElementsMatch(newIDs, queryResult.GetFieldsData()[0].GetScalars().GetLongData().GetData())
+
+	log.Info("TestRefreshLoad succeed")
+}
+
+func TestRefreshLoad(t *testing.T) {
+	suite.Run(t, new(RefreshLoadSuite))
+}
@@ -0,0 +1,85 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memoryusage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type MemoryUsageSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *MemoryUsageSuite) TestMemoryUsagePerCollection() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	collectionIDs := make([]int64, 0, 2)
+	for i := 0; i < 2; i++ {
+		collectionName := "TestMemoryUsagePerCollection" + funcutil.GenRandomStr()
+		s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+			CollectionName:   collectionName,
+			ChannelNum:       1,
+			SegmentNum:       2,
+			RowNumPerSegment: 2000,
+			Dim:              128,
+			ReplicaNumber:    1,
+		})
+		loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+			CollectionName: collectionName,
+		})
+		s.NoError(merr.CheckRPCCall(loadStatus, err))
+		s.WaitForLoad(ctx, collectionName)
+
+		describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+			CollectionName: collectionName,
+		})
+		s.NoError(merr.CheckRPCCall(describeResp, err))
+		collectionIDs = append(collectionIDs, describeResp.GetCollectionID())
+	}
+
+	s.Eventually(func() bool {
+		usage, err := c.GetQueryNodeMemoryUsage(ctx)
+		if err != nil {
+			log.Warn("fail to get querynode memory usage", zap.Error(err))
+			return false
+		}
+		log.Info("querynode memory usage", zap.Any("usage", usage))
+		for _, collectionID := range collectionIDs {
+			if usage[collectionID] <= 0 {
+				return false
+			}
+		}
+		return true
+	}, time.Minute, time.Second)
+}
+
+func TestMemoryUsage(t *testing.T) {
+	suite.Run(t, new(MemoryUsageSuite))
+}
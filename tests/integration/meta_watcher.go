@@ -22,8 +22,11 @@ import (
 	"fmt"
 	"path"
 	"sort"
+	"strconv"
 	"time"
 
+	"github.com/cockroachdb/errors"
+	v3rpc "go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
@@ -32,13 +35,17 @@ import (
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/streamingpb"
 )
 
 // MetaWatcher to observe meta data of milvus cluster
 type MetaWatcher interface {
 	ShowSessions() ([]*sessionutil.SessionRaw, error)
 	ShowSegments() ([]*datapb.SegmentInfo, error)
+	ListSegments(collectionID int64) ([]*datapb.SegmentInfo, error)
 	ShowReplicas() ([]*querypb.Replica, error)
+	ShowPChannels() ([]*streamingpb.PChannelMeta, error)
+	WatchSegments(ctx context.Context, revision int64) <-chan *datapb.SegmentInfo
 }
 
 type EtcdMetaWatcher struct {
@@ -59,11 +66,102 @@ func (watcher *EtcdMetaWatcher) ShowSegments() ([]*datapb.SegmentInfo, error) {
 	})
 }
 
+// ListSegments returns the segments of the collection in all states, sorted by id, with their binlogs filled.
+func (watcher *EtcdMetaWatcher) ListSegments(collectionID int64) ([]*datapb.SegmentInfo, error) {
+	metaBasePath := path.Join(watcher.rootPath, "/meta/datacoord-meta/s/", strconv.FormatInt(collectionID, 10)) + "/"
+	return listSegments(watcher.etcdCli, watcher.rootPath, metaBasePath, func(s *datapb.SegmentInfo) bool {
+		return s.GetCollectionID() == collectionID
+	})
+}
+
 func (watcher *EtcdMetaWatcher) ShowReplicas() ([]*querypb.Replica, error) {
 	metaBasePath := path.Join(watcher.rootPath, "/meta/querycoord-replica/")
 	return listReplicas(watcher.etcdCli, metaBasePath)
 }
 
+func (watcher *EtcdMetaWatcher) ShowPChannels() ([]*streamingpb.PChannelMeta, error) {
+	metaBasePath := path.Join(watcher.rootPath, "/meta/streamingcoord-meta/pchannel/") + "/"
+	return listPChannels(watcher.etcdCli, metaBasePath)
+}
+
+// WatchSegments watches the segment meta from the revision, 0 for the current one, and delivers every segment put,
+// binlogs of the segments are not filled. Once the revision to resume from is compacted away,
+// all segments are listed and delivered before the watch is re-established from the revision of the listing,
+// so no update of the segments is lost. The channel is closed once ctx is done.
+func (watcher *EtcdMetaWatcher) WatchSegments(ctx context.Context, revision int64) <-chan *datapb.SegmentInfo {
+	prefix := path.Join(watcher.rootPath, "/meta/datacoord-meta/s/") + "/"
+	segmentCh := make(chan *datapb.SegmentInfo)
+	deliver := func(value []byte) bool {
+		info := &datapb.SegmentInfo{}
+		if err := proto.Unmarshal(value, info); err != nil {
+			return true
+		}
+		select {
+		case segmentCh <- info:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(segmentCh)
+		for ctx.Err() == nil {
+			next, err := watcher.watchSegmentsFrom(ctx, prefix, revision, deliver)
+			if ctx.Err() != nil {
+				return
+			}
+			if !errors.Is(err, v3rpc.ErrCompacted) {
+				if err != nil {
+					log.Warn("segment watch broken, resume from the last revision", zap.Int64("revision", next), zap.Error(err))
+				}
+				revision = next
+				continue
+			}
+
+			log.Info("segment watch compacted, rewatch from the current revision", zap.Int64("revision", next))
+			resp, err := watcher.etcdCli.Get(ctx, prefix, clientv3.WithPrefix())
+			if err != nil {
+				log.Warn("failed to list segments", zap.Error(err))
+				continue
+			}
+			for _, kv := range resp.Kvs {
+				if !deliver(kv.Value) {
+					return
+				}
+			}
+			revision = resp.Header.GetRevision() + 1
+		}
+	}()
+	return segmentCh
+}
+
+// watchSegmentsFrom watches the prefix from the revision until the watch breaks,
+// it returns the revision to resume from along with the reason the watch broke.
+func (watcher *EtcdMetaWatcher) watchSegmentsFrom(ctx context.Context, prefix string, revision int64, deliver func([]byte) bool) (int64, error) {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	opts := []clientv3.OpOption{clientv3.WithPrefix()}
+	if revision > 0 {
+		opts = append(opts, clientv3.WithRev(revision))
+	}
+	for resp := range watcher.etcdCli.Watch(watchCtx, prefix, opts...) {
+		if err := resp.Err(); err != nil {
+			return revision, err
+		}
+		for _, event := range resp.Events {
+			revision = event.Kv.ModRevision + 1
+			if event.Type != clientv3.EventTypePut {
+				continue
+			}
+			if !deliver(event.Kv.Value) {
+				return revision, ctx.Err()
+			}
+		}
+	}
+	return revision, ctx.Err()
+}
+
 //=================== Below largely copied from birdwatcher ========================
 
 // listSessions returns all session
@@ -182,6 +280,27 @@ func listReplicas(cli *clientv3.Client, prefix string) ([]*querypb.Replica, erro
 	return replicas, nil
 }
 
+func listPChannels(cli *clientv3.Client, prefix string) ([]*streamingpb.PChannelMeta, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	resp, err := cli.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	pchannels := make([]*streamingpb.PChannelMeta, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		pchannel := &streamingpb.PChannelMeta{}
+		if err := proto.Unmarshal(kv.Value, pchannel); err != nil {
+			log.Warn("failed to unmarshal pchannel meta", zap.Error(err))
+			continue
+		}
+		pchannels = append(pchannels, pchannel)
+	}
+
+	return pchannels, nil
+}
+
 func PrettyReplica(replica *querypb.Replica) string {
 	res := fmt.Sprintf("ReplicaID: %d CollectionID: %d\n", replica.ID, replica.CollectionID)
 	res = res + fmt.Sprintf("Nodes:%v\n", replica.Nodes)
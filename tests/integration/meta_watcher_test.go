@@ -22,7 +22,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/suite"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
 
@@ -32,6 +34,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/milvus-io/milvus/pkg/v2/util/metric"
 )
 
@@ -151,6 +154,57 @@ func (s *MetaWatcherSuite) TestShowSegments() {
 	log.Info("TestShowSegments succeed")
 }
 
+func (s *MetaWatcherSuite) TestListSegments() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim        = 128
+		rowNum     = 1000
+		segmentNum = 2
+	)
+	prefix := "TestListSegments" + funcutil.GenRandomStr()
+
+	collectionIDs := make([]int64, 0, 2)
+	for i := 0; i < 2; i++ {
+		collectionName := prefix + strconv.Itoa(i)
+		s.CreateCollectionWithConfiguration(ctx, &CreateCollectionConfig{
+			CollectionName:   collectionName,
+			ChannelNum:       1,
+			SegmentNum:       segmentNum,
+			RowNumPerSegment: rowNum,
+			Dim:              dim,
+		})
+		describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+			CollectionName: collectionName,
+		})
+		s.Require().NoError(merr.CheckRPCCall(describeResp, err))
+		collectionIDs = append(collectionIDs, describeResp.GetCollectionID())
+	}
+
+	for _, collectionID := range collectionIDs {
+		segments, err := c.MetaWatcher.ListSegments(collectionID)
+		s.Require().NoError(err)
+		s.GreaterOrEqual(len(segments), segmentNum)
+
+		// small segments may be compacted meanwhile, while the rows of the live ones always add up
+		var rows int64
+		for _, segment := range segments {
+			s.Equal(collectionID, segment.GetCollectionID())
+			if segment.GetState() == commonpb.SegmentState_Dropped {
+				continue
+			}
+			s.Equal(commonpb.SegmentState_Flushed, segment.GetState())
+			s.NotEmpty(segment.GetBinlogs())
+			rows += segment.GetNumOfRows()
+		}
+		s.EqualValues(segmentNum*rowNum, rows)
+	}
+
+	log.Info("TestListSegments succeed")
+}
+
 func (s *MetaWatcherSuite) TestShowReplicas() {
 	c := s.Cluster
 	ctx, cancel := context.WithCancel(c.GetContext())
@@ -320,6 +374,64 @@ func (s *MetaWatcherSuite) TestShowReplicas() {
 	log.Info("TestShowReplicas succeed")
 }
 
+func (s *MetaWatcherSuite) TestWatchSegmentsAfterCompaction() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		rowNum = 1000
+	)
+	collectionName := "TestWatchSegmentsAfterCompaction" + funcutil.GenRandomStr()
+
+	resp, err := c.EtcdCli.Get(ctx, "/", clientv3.WithCountOnly())
+	s.Require().NoError(err)
+	revision := resp.Header.GetRevision()
+
+	s.CreateCollectionWithConfiguration(ctx, &CreateCollectionConfig{
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       1,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collectionName,
+	})
+	s.Require().NoError(merr.CheckRPCCall(describeResp, err))
+
+	// the revision the watch starts from is compacted away along with the puts of the flushed segment
+	compacted, err := c.CompactEtcd(ctx)
+	s.Require().NoError(err)
+	s.Require().Greater(compacted, revision)
+	segmentCh := c.MetaWatcher.WatchSegments(ctx, revision)
+
+	waitFlushed := func(excluded ...int64) int64 {
+		for {
+			select {
+			case segment, ok := <-segmentCh:
+				s.Require().True(ok)
+				if segment.GetCollectionID() == describeResp.GetCollectionID() &&
+					segment.GetState() == commonpb.SegmentState_Flushed &&
+					!lo.Contains(excluded, segment.GetID()) {
+					return segment.GetID()
+				}
+			case <-ctx.Done():
+				s.FailNow("no flushed segment delivered until ctx done")
+			}
+		}
+	}
+	// the flushed segment is delivered by the listing after the rewatch
+	flushed := waitFlushed()
+
+	// and the watch keeps delivering updates from then on
+	s.NoError(s.InsertAndFlush(ctx, "", collectionName, rowNum, dim))
+	s.NotEqual(flushed, waitFlushed(flushed))
+
+	log.Info("TestWatchSegmentsAfterCompaction succeed")
+}
+
 func TestMetaWatcher(t *testing.T) {
 	suite.Run(t, new(MetaWatcherSuite))
 }
@@ -0,0 +1,101 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metasnapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/coordinator/coordclient"
+	"github.com/milvus-io/milvus/internal/streamingcoord/server/broadcaster/registry"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type MetaSnapshotSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *MetaSnapshotSuite) TestRecoverFromSnapshot() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim            = 128
+		dbName         = ""
+		collectionNum  = 3
+		droppedCollNum = 1
+	)
+	prefix := "TestRecoverFromSnapshot" + funcutil.GenRandomStr()
+
+	collections := make([]string, 0, collectionNum)
+	for i := 0; i < collectionNum; i++ {
+		collectionName := prefix + "_" + funcutil.GenRandomStr()
+		schema := integration.ConstructSchema(collectionName, dim, true)
+		marshaledSchema, err := proto.Marshal(schema)
+		s.NoError(err)
+		createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			Schema:         marshaledSchema,
+			ShardsNum:      common.DefaultShardsNum,
+		})
+		s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+		collections = append(collections, collectionName)
+	}
+
+	ts, err := c.TriggerMetaSnapshot(ctx)
+	s.Require().NoError(err)
+	s.NoError(c.CheckMetaSnapshot(ctx, dbName, ts, collections))
+
+	// changes after the snapshot shall not be seen in it
+	for _, collectionName := range collections[:droppedCollNum] {
+		dropStatus, err := c.Proxy.DropCollection(ctx, &milvuspb.DropCollectionRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+		})
+		s.NoError(merr.CheckRPCCall(dropStatus, err))
+	}
+	s.NoError(c.CheckMetaSnapshot(ctx, dbName, ts, collections))
+
+	c.StopRootCoord()
+	registry.ResetRegistration()
+	coordclient.ResetRegistration()
+	c.StartRootCoord()
+	log.Info("rootcoord restarted")
+
+	// both the snapshot and the latest meta are recovered from etcd
+	s.Eventually(func() bool {
+		return c.CheckMetaSnapshot(ctx, dbName, ts, collections) == nil
+	}, time.Minute, time.Second)
+	s.NoError(c.CheckMetaSnapshot(ctx, dbName, 0, collections[droppedCollNum:]))
+
+	log.Info("TestRecoverFromSnapshot succeed")
+}
+
+func TestMetaSnapshot(t *testing.T) {
+	suite.Run(t, new(MetaSnapshotSuite))
+}
@@ -0,0 +1,88 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type MetricsSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *MetricsSuite) TestGetMetrics() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+	)
+	collectionName := "TestGetMetrics" + funcutil.GenRandomStr()
+
+	s.Require().NoError(c.CreateAutoIDCollection(ctx, dbName, collectionName, dim, 1))
+	_, err := c.InsertAutoID(ctx, dbName, collectionName, rowNum, dim)
+	s.Require().NoError(err)
+
+	text, err := c.GetMetrics(typeutil.ProxyRole)
+	s.Require().NoError(err)
+	s.Contains(text, "# TYPE milvus_proxy_insert_vectors_count counter")
+
+	// the inserted vectors are counted by the proxy, and the latency of the insert is observed once
+	parsed, err := c.ParsedMetrics(typeutil.ProxyRole)
+	s.Require().NoError(err)
+	var inserted, mutations float64
+	for series, value := range parsed {
+		if !strings.Contains(series, fmt.Sprintf("collection_name=%q", collectionName)) {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(series, "milvus_proxy_insert_vectors_count{"):
+			inserted += value
+		case strings.HasPrefix(series, "milvus_proxy_mutation_latency_count{"):
+			mutations += value
+		}
+	}
+	s.EqualValues(rowNum, inserted)
+	s.EqualValues(1, mutations)
+
+	// every role of the cluster serves its metrics
+	_, err = c.GetMetrics(typeutil.QueryNodeRole)
+	s.NoError(err)
+
+	_, err = c.GetMetrics("unknown")
+	s.Error(err)
+
+	log.Info("TestGetMetrics succeed")
+}
+
+func TestMetrics(t *testing.T) {
+	suite.Run(t, new(MetricsSuite))
+}
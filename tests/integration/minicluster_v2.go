@@ -21,7 +21,10 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"os"
 	"path"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
 
@@ -30,14 +33,17 @@ import (
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/internal/coordinator/coordclient"
+	"github.com/milvus-io/milvus/internal/datacoord"
 	grpcdatacoord "github.com/milvus-io/milvus/internal/distributed/datacoord"
 	grpcdatacoordclient "github.com/milvus-io/milvus/internal/distributed/datacoord/client"
 	grpcdatanode "github.com/milvus-io/milvus/internal/distributed/datanode"
@@ -52,6 +58,7 @@ import (
 	grpcrootcoordclient "github.com/milvus-io/milvus/internal/distributed/rootcoord/client"
 	"github.com/milvus-io/milvus/internal/distributed/streaming"
 	"github.com/milvus-io/milvus/internal/distributed/streamingnode"
+	"github.com/milvus-io/milvus/internal/rootcoord"
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/streamingcoord/server/broadcaster/registry"
 	"github.com/milvus-io/milvus/internal/types"
@@ -60,35 +67,33 @@ import (
 	"github.com/milvus-io/milvus/internal/util/streamingutil"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/util/etcd"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
 )
 
 var params *paramtable.ComponentParam = paramtable.Get()
 
-var (
-	initOnce  sync.Once
-	configMap map[string]string
-)
+// testPathSeq tells apart the test paths of the clusters started by the same process within a second.
+var testPathSeq atomic.Int64
 
+// DefaultParams returns the default params of a new cluster. Each call gets a unique test path,
+// which the etcd root path, channel prefix and storage paths are derived from,
+// so that clusters started one after another, even by concurrent test processes, never share their data.
 func DefaultParams() map[string]string {
-	initOnce.Do(func() {
-		testPath := fmt.Sprintf("integration-test-%d", time.Now().Unix())
-
-		// Notice: don't use ParamItem.Key here, the config key will be empty before param table init
-		configMap = map[string]string{
-			"mq.type":                           "rocksmq",
-			"etcd.rootPath":                     testPath,
-			"msgChannel.chanNamePrefix.cluster": testPath,
-			"minio.rootPath":                    testPath,
-			"localStorage.path":                 path.Join("/tmp", testPath),
-			"common.storageType":                "local",
-			"dataNode.memory.forceSyncEnable":   "false", // local execution will print too many logs
-			"common.gracefulStopTimeout":        "30",
-		}
-	})
-
-	return configMap
+	testPath := fmt.Sprintf("integration-test-%d-%d-%d", time.Now().Unix(), os.Getpid(), testPathSeq.Inc())
+
+	// Notice: don't use ParamItem.Key here, the config key will be empty before param table init
+	return map[string]string{
+		"mq.type":                           "rocksmq",
+		"etcd.rootPath":                     testPath,
+		"msgChannel.chanNamePrefix.cluster": testPath,
+		"minio.rootPath":                    testPath,
+		"localStorage.path":                 path.Join("/tmp", testPath),
+		"common.storageType":                "local",
+		"dataNode.memory.forceSyncEnable":   "false", // local execution will print too many logs
+		"common.gracefulStopTimeout":        "30",
+	}
 }
 
 type MiniClusterV2 struct {
@@ -128,18 +133,276 @@ type MiniClusterV2 struct {
 	datanodes      []*grpcdatanode.Server
 	dnid           atomic.Int64
 	streamingnodes []*streamingnode.Server
-
-	clientConn *grpc.ClientConn
-	Extension  *ReportChanExtension
+	snid           atomic.Int64
+	proxies        []*grpcproxy.Server
+	pid            atomic.Int64
+	forcedNodeIDs  map[string]int64
+
+	streamingNodeNum        int
+	preSeededKVs            map[string][]byte
+	profileDir              string
+	cpuProfile              *os.File
+	proxyEtcd               bool
+	etcdLatency             time.Duration
+	etcdProxy               *etcdLatencyProxy
+	dataCoordOpts           []datacoord.Option
+	storageFaults           *storageFaults
+	searchFaults            map[int64]*faultyQueryNode
+	fixedPorts              map[string]int
+	mqType                  string
+	embedEtcd               bool
+	baseFactory             dependency.Factory
+	withFactory             bool
+	portRangeStart          int
+	portRangeEnd            int
+	leasedPorts             []int
+	rootPassword            string
+	tlsCertFile             string
+	tlsKeyFile              string
+	tlsCAFile               string
+	tlsCreds                credentials.TransportCredentials
+	streamingService        *bool
+	restoreStreamingService func()
+	clockOffset             atomic.Duration
+
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
+	dialOpts           []grpc.DialOption
+
+	clientConn  *grpc.ClientConn
+	unauthConns []*grpc.ClientConn
+	Extension   *ReportChanExtension
 }
 
 type OptionV2 func(cluster *MiniClusterV2)
 
+// WithStreamingNodes brings up n streaming nodes in total during Start when the streaming service is enabled.
+func WithStreamingNodes(n int) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.streamingNodeNum = n
+	}
+}
+
+// WithStreamingService turns the streaming service of the cluster on or off regardless of the environment,
+// so that the streaming node is brought up and torn down with the cluster only if enabled.
+// Note that components tell whether the streaming service is enabled by the process-wide environment,
+// so it's set for the cluster on Start and restored on Stop, clusters running at the same time can't differ in it.
+func WithStreamingService(enabled bool) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.streamingService = &enabled
+	}
+}
+
+// WithReportBuffer buffers the report channel of the Extension by size, so that the hook reports can be counted.
+func WithReportBuffer(size int) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.Extension = InitReportExtension(size)
+	}
+}
+
+// WithPreSeededEtcd writes the kvs into etcd before any component starts,
+// keys are relative to the etcd root path of the cluster.
+func WithPreSeededEtcd(kvs map[string][]byte) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.preSeededKVs = kvs
+	}
+}
+
+// WithProfiling collects the cpu profile from Start to Stop of the cluster,
+// and writes it along with the heap profile at Stop into dir.
+func WithProfiling(dir string) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.profileDir = dir
+	}
+}
+
+// WithQueryNodeLoadConcurrency lets querycoord execute at most n tasks on each querynode at the same time
+// by queryCoord.taskExecutionCap, so that a querynode loads at most n segments or channels concurrently.
+// Release and balance tasks are capped together with the loads.
+func WithQueryNodeLoadConcurrency(n int) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.params[params.QueryCoordCfg.TaskExecutionCap.Key] = strconv.Itoa(n)
+	}
+}
+
+// WithMaxReadConcurrentRatio sets queryNode.scheduler.maxReadConcurrentRatio, so that each querynode runs
+// at most cpu number * ratio read tasks, searches and queries, at the same time, and at least one.
+// Querynode has no concurrency dedicated to reducing results, the results of segments are reduced
+// by the read task searching them, so it bounds how many reduces run concurrently as well.
+func WithMaxReadConcurrentRatio(ratio float64) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.params[params.QueryNodeCfg.MaxReadConcurrency.Key] = strconv.FormatFloat(ratio, 'f', -1, 64)
+	}
+}
+
+// WithEtcdLatency puts a proxy in front of etcd, which delays every request to etcd by d,
+// all components and the etcd client of the cluster connect etcd through the proxy.
+// The latency can be changed at runtime by SetEtcdLatency, so WithEtcdLatency(0) starts the cluster on a healthy etcd
+// which can be degraded later.
+func WithEtcdLatency(d time.Duration) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.proxyEtcd = true
+		cluster.etcdLatency = d
+	}
+}
+
+// WithChannelAssignment overrides the channel assign policy of datacoord,
+// every channel to assign is watched by the datanode picked by assign among the candidates, or left unassigned by KeepUnassigned.
+// Channels are never rebalanced among datanodes when the assignment is overridden,
+// and it takes no effect when the streaming service is enabled.
+func WithChannelAssignment(assign func(channel string, candidates []int64) int64) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.dataCoordOpts = append(cluster.dataCoordOpts, datacoord.WithChannelPolicyFactory(&pinnedChannelPolicyFactory{assign: assign}))
+	}
+}
+
+// WithSegmentIdleSeal makes growing segments sealed once they accept no dml for d,
+// no matter how small they are.
+func WithSegmentIdleSeal(d time.Duration) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.params[params.DataCoordCfg.SegmentMaxIdleTime.Key] = strconv.Itoa(int(d.Seconds()))
+		cluster.params[params.DataCoordCfg.SegmentMinSizeFromIdleToSealed.Key] = "0"
+	}
+}
+
+// WithCompactionConcurrency allows each datanode to run at most n compactions at the same time,
+// each compaction of any kind takes one of the n slots of the datanode.
+func WithCompactionConcurrency(n int) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.params[params.DataNodeCfg.SlotCap.Key] = strconv.Itoa(n)
+		cluster.params[params.DataCoordCfg.MixCompactionSlotUsage.Key] = "1"
+		cluster.params[params.DataCoordCfg.L0DeleteCompactionSlotUsage.Key] = "1"
+		cluster.params[params.DataCoordCfg.ClusteringCompactionSlotUsage.Key] = "1"
+	}
+}
+
+// WithClientInterceptors adds the interceptors to the clients of the cluster dialing proxy, e.g. MilvusClient.
+// They are chained before the default retry interceptor, so every call is observed once no matter how many times it's retried.
+func WithClientInterceptors(unary []grpc.UnaryClientInterceptor, stream []grpc.StreamClientInterceptor) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.unaryInterceptors = append(cluster.unaryInterceptors, unary...)
+		cluster.streamInterceptors = append(cluster.streamInterceptors, stream...)
+	}
+}
+
+// WithClientDialOptions adds the dial options to the clients of the cluster dialing proxy, e.g. MilvusClient.
+// They are applied after the default ones, so they replace the defaults of the same kind, e.g. keepalive or connect params,
+// while interceptors are chained after the default retry interceptor and observe every attempt.
+// WithBlock stays mandatory, and so do the transport credentials, the ones of WithTLS or insecure ones, unless other credentials are given.
+func WithClientDialOptions(opts ...grpc.DialOption) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.dialOpts = append(cluster.dialOpts, opts...)
+	}
+}
+
+// WithPorts binds the servers of the roles to the fixed ports instead of the randomly allocated ones,
+// keyed by role, e.g. typeutil.ProxyRole. Only the coordinators, proxy and the first datanode and querynode are supported.
+// Starting the cluster fails if any of the ports is not free.
+func WithPorts(ports map[string]int) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		if cluster.fixedPorts == nil {
+			cluster.fixedPorts = make(map[string]int)
+		}
+		for role, port := range ports {
+			cluster.fixedPorts[role] = port
+		}
+	}
+}
+
+// WithPortRange allocates the ports of the cluster from [start, end) instead of letting the OS pick them.
+// Either way the ports are leased until Stop, so clusters of the same process never pick a port leased by another one
+// before it's bound. Starting the cluster fails if the range is not within [1, 65536).
+func WithPortRange(start, end int) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.portRangeStart = start
+		cluster.portRangeEnd = end
+	}
+}
+
+// WithStoragePath pins localStorage.path of the cluster to p instead of a unique one under /tmp,
+// note that all data under it is removed on Stop.
+func WithStoragePath(p string) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.params[params.LocalStorageCfg.Path.Key] = p
+	}
+}
+
+// WithAuthEnabled brings up the cluster with authorization enabled, and root is created with rootPassword,
+// or the default password of Milvus if it's empty, by rootcoord at its first start.
+// MilvusClient and the other clients of the cluster dialing proxy carry the credential of root on every call,
+// while the one of GetUnauthenticatedClient carries none. Authorization is disabled again on Stop.
+func WithAuthEnabled(rootPassword string) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		if rootPassword == "" {
+			rootPassword = defaultRootPassword
+		}
+		cluster.rootPassword = rootPassword
+		cluster.params[params.CommonCfg.AuthorizationEnabled.Key] = "true"
+		cluster.params[params.CommonCfg.DefaultRootPassword.Key] = rootPassword
+	}
+}
+
+// WithTLS serves the external grpc of proxy with mutual tls, proxy presents the certificate of certFile and keyFile,
+// and only accepts clients presenting a certificate signed by the ca of caFile.
+// The clients of the cluster dialing proxy, e.g. MilvusClient, present the same certificate and verify proxy by the ca,
+// expecting localhost as its server name. The http server of proxy is disabled since it can't share the port with tls.
+// Starting the cluster fails if any of the files can't be loaded.
+func WithTLS(certFile, keyFile, caFile string) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.tlsCertFile = certFile
+		cluster.tlsKeyFile = keyFile
+		cluster.tlsCAFile = caFile
+		cluster.params[params.ProxyGrpcServerCfg.TLSMode.Key] = "2"
+		cluster.params[params.ProxyGrpcServerCfg.ServerPemPath.Key] = certFile
+		cluster.params[params.ProxyGrpcServerCfg.ServerKeyPath.Key] = keyFile
+		cluster.params[params.ProxyGrpcServerCfg.CaPemPath.Key] = caFile
+		cluster.params[params.HTTPCfg.Enabled.Key] = "false"
+	}
+}
+
+// supportedMqTypes are the message queues the cluster can run on.
+var supportedMqTypes = []string{"rocksmq", "pulsar", "kafka"}
+
+// WithMqType runs the cluster on the message queue of mqType instead of rocksmq, one of supportedMqTypes.
+// Starting the cluster fails if mqType is not supported. Pulsar and kafka are expected to be reachable with their configs.
+func WithMqType(mqType string) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.mqType = mqType
+	}
+}
+
+// WithEmbeddedEtcd runs the cluster on an in-process etcd server instead of the one of etcd.endpoints,
+// its data is kept under localStorage.path and removed on Stop.
+// With etcd.use.embed set, EtcdCli and the clients of all components are in-process clients of the same embedded server,
+// so MetaWatcher and the helpers reading etcd work as usual, while WithEtcdLatency has no endpoint to sit in front of.
+// The embedded server listens on the default ports of etcd, so the suite shall not start its own etcd by SetupEmbedEtcd.
+// It's also a singleton of the process which can't be restarted once stopped,
+// so only one cluster of a test binary can run on it.
+func WithEmbeddedEtcd() OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.embedEtcd = true
+	}
+}
+
+// WithFactory builds the components and ChunkManager of the cluster with the factory instead of the default one,
+// e.g. to run on a specific object storage. The factory is still wrapped so that SetStorageReadOnly works.
+// Starting the cluster fails if the factory is nil.
+func WithFactory(f dependency.Factory) OptionV2 {
+	return func(cluster *MiniClusterV2) {
+		cluster.baseFactory = f
+		cluster.withFactory = true
+	}
+}
+
 func StartMiniClusterV2(ctx context.Context, opts ...OptionV2) (*MiniClusterV2, error) {
 	cluster := &MiniClusterV2{
-		ctx:  ctx,
-		qnid: *atomic.NewInt64(10000),
-		dnid: *atomic.NewInt64(20000),
+		ctx:              ctx,
+		qnid:             *atomic.NewInt64(10000),
+		dnid:             *atomic.NewInt64(20000),
+		snid:             *atomic.NewInt64(30000),
+		pid:              *atomic.NewInt64(40000),
+		streamingNodeNum: 1,
+		storageFaults:    &storageFaults{},
 	}
 	paramtable.Init()
 	cluster.Extension = InitReportExtension()
@@ -148,13 +411,69 @@ func StartMiniClusterV2(ctx context.Context, opts ...OptionV2) (*MiniClusterV2,
 	for _, opt := range opts {
 		opt(cluster)
 	}
+	if cluster.mqType != "" {
+		if !slices.Contains(supportedMqTypes, cluster.mqType) {
+			return nil, errors.Newf("mq type %s is not supported, valid values: %v", cluster.mqType, supportedMqTypes)
+		}
+		cluster.params[params.MQCfg.Type.Key] = cluster.mqType
+	}
+	if (cluster.portRangeStart != 0 || cluster.portRangeEnd != 0) &&
+		(cluster.portRangeStart <= 0 || cluster.portRangeEnd > 65536 || cluster.portRangeStart >= cluster.portRangeEnd) {
+		return nil, errors.Newf("invalid port range [%d, %d)", cluster.portRangeStart, cluster.portRangeEnd)
+	}
+	if cluster.withFactory && cluster.baseFactory == nil {
+		return nil, errors.New("factory of the cluster is nil")
+	}
+	if cluster.tlsCertFile != "" {
+		creds, err := newClientTLSCredentials(cluster.tlsCertFile, cluster.tlsKeyFile, cluster.tlsCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cluster.tlsCreds = creds
+	}
+	if cluster.streamingService != nil {
+		cluster.setStreamingService(*cluster.streamingService)
+	}
 	for k, v := range cluster.params {
 		params.Save(k, v)
 	}
 	paramtable.SetRole(typeutil.StandaloneRole)
 
+	ports, err := cluster.allocatePorts(typeutil.RootCoordRole, typeutil.DataCoordRole, typeutil.QueryCoordRole,
+		typeutil.DataNodeRole, typeutil.QueryNodeRole, typeutil.ProxyRole)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("minicluster ports", zap.Any("ports", ports))
+	params.RootCoordGrpcServerCfg.IP = "localhost"
+	params.QueryCoordGrpcServerCfg.IP = "localhost"
+	params.DataCoordGrpcServerCfg.IP = "localhost"
+	params.ProxyGrpcServerCfg.IP = "localhost"
+	params.QueryNodeGrpcServerCfg.IP = "localhost"
+	params.DataNodeGrpcServerCfg.IP = "localhost"
+	params.StreamingNodeGrpcServerCfg.IP = "localhost"
+	params.Save(params.RootCoordGrpcServerCfg.Port.Key, fmt.Sprint(ports[typeutil.RootCoordRole]))
+	params.Save(params.DataCoordGrpcServerCfg.Port.Key, fmt.Sprint(ports[typeutil.DataCoordRole]))
+	params.Save(params.QueryCoordGrpcServerCfg.Port.Key, fmt.Sprint(ports[typeutil.QueryCoordRole]))
+	params.Save(params.DataNodeGrpcServerCfg.Port.Key, fmt.Sprint(ports[typeutil.DataNodeRole]))
+	params.Save(params.QueryNodeGrpcServerCfg.Port.Key, fmt.Sprint(ports[typeutil.QueryNodeRole]))
+	params.Save(params.ProxyGrpcServerCfg.Port.Key, fmt.Sprint(ports[typeutil.ProxyRole]))
+
 	// setup etcd client
 	etcdConfig := &paramtable.Get().EtcdCfg
+	if cluster.embedEtcd {
+		if err := cluster.startEmbeddedEtcd(); err != nil {
+			return nil, err
+		}
+	}
+	if cluster.proxyEtcd {
+		proxy, err := newEtcdLatencyProxy(etcdConfig.Endpoints.GetAsStrings()[0], cluster.etcdLatency)
+		if err != nil {
+			return nil, err
+		}
+		cluster.etcdProxy = proxy
+		params.Save(etcdConfig.Endpoints.Key, proxy.Addr())
+	}
 	etcdCli, err := etcd.GetEtcdClient(
 		etcdConfig.UseEmbedEtcd.GetAsBool(),
 		etcdConfig.EtcdUseSSL.GetAsBool(),
@@ -168,6 +487,12 @@ func StartMiniClusterV2(ctx context.Context, opts ...OptionV2) (*MiniClusterV2,
 	}
 	cluster.EtcdCli = etcdCli
 
+	for key, value := range cluster.preSeededKVs {
+		if _, err := etcdCli.Put(ctx, path.Join(etcdConfig.RootPath.GetValue(), key), string(value)); err != nil {
+			return nil, err
+		}
+	}
+
 	coordclient.ResetRegistration()
 	registry.ResetRegistration()
 	streaming.Init()
@@ -177,25 +502,6 @@ func StartMiniClusterV2(ctx context.Context, opts ...OptionV2) (*MiniClusterV2,
 		etcdCli:  cluster.EtcdCli,
 	}
 
-	ports, err := cluster.GetAvailablePorts(7)
-	if err != nil {
-		return nil, err
-	}
-	log.Info("minicluster ports", zap.Ints("ports", ports))
-	params.RootCoordGrpcServerCfg.IP = "localhost"
-	params.QueryCoordGrpcServerCfg.IP = "localhost"
-	params.DataCoordGrpcServerCfg.IP = "localhost"
-	params.ProxyGrpcServerCfg.IP = "localhost"
-	params.QueryNodeGrpcServerCfg.IP = "localhost"
-	params.DataNodeGrpcServerCfg.IP = "localhost"
-	params.StreamingNodeGrpcServerCfg.IP = "localhost"
-	params.Save(params.RootCoordGrpcServerCfg.Port.Key, fmt.Sprint(ports[0]))
-	params.Save(params.DataCoordGrpcServerCfg.Port.Key, fmt.Sprint(ports[1]))
-	params.Save(params.QueryCoordGrpcServerCfg.Port.Key, fmt.Sprint(ports[2]))
-	params.Save(params.DataNodeGrpcServerCfg.Port.Key, fmt.Sprint(ports[3]))
-	params.Save(params.QueryNodeGrpcServerCfg.Port.Key, fmt.Sprint(ports[4]))
-	params.Save(params.ProxyGrpcServerCfg.Port.Key, fmt.Sprint(ports[6]))
-
 	// setup clients
 	cluster.RootCoordClient, err = grpcrootcoordclient.NewClient(ctx)
 	if err != nil {
@@ -224,152 +530,438 @@ func StartMiniClusterV2(ctx context.Context, opts ...OptionV2) (*MiniClusterV2,
 	}
 
 	// setup servers
-	cluster.factory = dependency.MockDefaultFactory(true, params)
+	if cluster.baseFactory == nil {
+		cluster.baseFactory = dependency.MockDefaultFactory(true, params)
+	}
+	cluster.factory = &faultyFactory{Factory: cluster.baseFactory, faults: cluster.storageFaults}
 	chunkManager, err := cluster.factory.NewPersistentStorageChunkManager(cluster.ctx)
 	if err != nil {
 		return nil, err
 	}
 	cluster.ChunkManager = chunkManager
 
-	cluster.RootCoord, err = grpcrootcoord.NewServer(ctx, cluster.factory)
-	if err != nil {
+	if err := cluster.newServers(ctx); err != nil {
 		return nil, err
 	}
-	cluster.DataCoord, err = grpcdatacoord.NewServer(ctx, cluster.factory)
+	return cluster, nil
+}
+
+// newServers creates the servers of all components, which are started by startComponents.
+func (cluster *MiniClusterV2) newServers(ctx context.Context) error {
+	var err error
+	cluster.RootCoord, err = cluster.newRootCoord(ctx)
+	if err != nil {
+		return err
+	}
+	cluster.DataCoord, err = grpcdatacoord.NewServer(ctx, cluster.factory, cluster.dataCoordOpts...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	cluster.QueryCoord, err = grpcquerycoord.NewServer(ctx, cluster.factory)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	cluster.Proxy, err = grpcproxy.NewServer(ctx, cluster.factory)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	cluster.DataNode, err = grpcdatanode.NewServer(ctx, cluster.factory)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if streamingutil.IsStreamingServiceEnabled() {
 		cluster.StreamingNode, err = streamingnode.NewServer(ctx, cluster.factory)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 	cluster.QueryNode, err = grpcquerynode.NewServer(ctx, cluster.factory)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return cluster, nil
+	return nil
 }
 
-func (cluster *MiniClusterV2) AddQueryNodes(k int) []*grpcquerynode.Server {
-	servers := make([]*grpcquerynode.Server, k)
+// AddQueryNodes adds k extra querynodes one after another,
+// it stops at the first querynode failing to start and returns the error along with the querynodes added so far.
+func (cluster *MiniClusterV2) AddQueryNodes(k int) ([]*grpcquerynode.Server, error) {
+	servers := make([]*grpcquerynode.Server, 0, k)
 	for i := 0; i < k; i++ {
-		servers = append(servers, cluster.AddQueryNode())
+		node, err := cluster.AddQueryNode()
+		if err != nil {
+			return servers, err
+		}
+		servers = append(servers, node)
 	}
-	return servers
+	return servers, nil
 }
 
-func (cluster *MiniClusterV2) AddQueryNode() *grpcquerynode.Server {
+// AddQueryNode adds an extra querynode, it returns the error if the querynode fails to start,
+// e.g. its registration is rejected for a conflicting node id. The failed querynode is stopped before return.
+func (cluster *MiniClusterV2) AddQueryNode() (_ *grpcquerynode.Server, err error) {
 	cluster.ptmu.Lock()
 	defer cluster.ptmu.Unlock()
-	cluster.qnid.Inc()
-	id := cluster.qnid.Load()
+	id := cluster.nextNodeID(typeutil.QueryNodeRole, &cluster.qnid)
 	oid := paramtable.GetNodeID()
 	log.Info(fmt.Sprintf("adding extra querynode with id:%d", id))
 	paramtable.SetNodeID(id)
+	defer paramtable.SetNodeID(oid)
 	node, err := grpcquerynode.NewServer(context.TODO(), cluster.factory)
 	if err != nil {
-		return nil
+		return nil, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("querynode %d failed to start: %v", id, r)
+		}
+		if err != nil {
+			node.Stop()
+		}
+	}()
+	if err := node.Prepare(); err != nil {
+		return nil, err
+	}
+	if err := node.Run(); err != nil {
+		return nil, err
 	}
-	runComponent(node)
-	paramtable.SetNodeID(oid)
 
-	req := &milvuspb.GetComponentStatesRequest{}
-	resp, err := node.GetComponentStates(context.TODO(), req)
-	if err != nil {
-		return nil
+	resp, err := node.GetComponentStates(context.TODO(), &milvuspb.GetComponentStatesRequest{})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, errors.Wrapf(err, "failed to get component states of querynode %d", id)
 	}
 	log.Info(fmt.Sprintf("querynode %d ComponentStates:%v", id, resp))
 	cluster.querynodes = append(cluster.querynodes, node)
-	return node
+	return node, nil
 }
 
-func (cluster *MiniClusterV2) AddDataNode() *grpcdatanode.Server {
+// RestartQueryNode stops the extra querynode added by AddQueryNode and brings up a new one with the same node id in its place,
+// so that querycoord sees the same node rejoining rather than a new one.
+// It blocks until the new querynode is healthy and registered again.
+// If the new querynode fails to start, it's stopped and the restarted querynode is no longer tracked by the cluster.
+func (cluster *MiniClusterV2) RestartQueryNode(server *grpcquerynode.Server) (err error) {
 	cluster.ptmu.Lock()
 	defer cluster.ptmu.Unlock()
-	cluster.qnid.Inc()
-	id := cluster.qnid.Load()
+	idx := slices.Index(cluster.querynodes, server)
+	if idx < 0 {
+		return errors.New("querynode to restart is not an extra querynode of the cluster")
+	}
+	id := server.GetQueryNode().GetNodeID()
+	if err := server.Stop(); err != nil {
+		return errors.Wrapf(err, "failed to stop querynode %d", id)
+	}
+	log.Info(fmt.Sprintf("restarting extra querynode with id:%d", id))
+
+	oid := paramtable.GetNodeID()
+	paramtable.SetNodeID(id)
+	defer paramtable.SetNodeID(oid)
+	node, err := grpcquerynode.NewServer(context.TODO(), cluster.factory)
+	if err != nil {
+		cluster.querynodes = slices.Delete(cluster.querynodes, idx, idx+1)
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("querynode %d failed to restart: %v", id, r)
+		}
+		// the stopped querynode is gone either way, so is the failed one
+		if err != nil {
+			node.Stop()
+			cluster.querynodes = slices.Delete(cluster.querynodes, idx, idx+1)
+		}
+	}()
+	if err := node.Prepare(); err != nil {
+		return err
+	}
+	if err := node.Run(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cluster.ctx, time.Second*120)
+	defer cancel()
+	if err := cluster.waitForQueryNodeRejoined(ctx, node, id); err != nil {
+		return err
+	}
+	cluster.querynodes[idx] = node
+	return nil
+}
+
+// waitForQueryNodeRejoined waits until the querynode of id is healthy and registered in etcd.
+func (cluster *MiniClusterV2) waitForQueryNodeRejoined(ctx context.Context, node *grpcquerynode.Server, id int64) error {
+	for {
+		resp, err := node.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+		healthy := merr.CheckRPCCall(resp, err) == nil && resp.GetState().GetStateCode() == commonpb.StateCode_Healthy
+		sessions, err := cluster.MetaWatcher.ShowSessions()
+		if err != nil {
+			return err
+		}
+		registered := false
+		for _, session := range sessions {
+			if session.ServerName == typeutil.QueryNodeRole && session.ServerID == id {
+				registered = true
+			}
+		}
+		if healthy && registered {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "querynode %d is not rejoined, healthy: %t, registered: %t", id, healthy, registered)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// RemoveQueryNode stops the extra querynode added by AddQueryNode and removes it from the cluster,
+// the other querynodes are left untouched. The main querynode can't be removed.
+func (cluster *MiniClusterV2) RemoveQueryNode(server *grpcquerynode.Server) error {
+	cluster.ptmu.Lock()
+	defer cluster.ptmu.Unlock()
+	if server == cluster.QueryNode {
+		return errors.New("the main querynode of the cluster can't be removed")
+	}
+	idx := slices.Index(cluster.querynodes, server)
+	if idx < 0 {
+		return errors.New("querynode to remove is not an extra querynode of the cluster")
+	}
+	id := server.GetQueryNode().GetNodeID()
+	if err := server.Stop(); err != nil {
+		return errors.Wrapf(err, "failed to stop querynode %d", id)
+	}
+	cluster.querynodes = slices.Delete(cluster.querynodes, idx, idx+1)
+	log.Info(fmt.Sprintf("removed extra querynode with id:%d", id))
+	return nil
+}
+
+// ForceNodeID makes the next node of the role added to the cluster take id,
+// instead of a fresh one, so that node id conflicts can be simulated.
+func (cluster *MiniClusterV2) ForceNodeID(role string, id int64) {
+	cluster.ptmu.Lock()
+	defer cluster.ptmu.Unlock()
+	if cluster.forcedNodeIDs == nil {
+		cluster.forcedNodeIDs = make(map[string]int64)
+	}
+	cluster.forcedNodeIDs[role] = id
+}
+
+// nextNodeID returns the forced node id of the role if any, otherwise a fresh one from counter,
+// the caller must hold ptmu.
+func (cluster *MiniClusterV2) nextNodeID(role string, counter *atomic.Int64) int64 {
+	if id, ok := cluster.forcedNodeIDs[role]; ok {
+		delete(cluster.forcedNodeIDs, role)
+		return id
+	}
+	return counter.Inc()
+}
+
+// AddDataNode adds an extra datanode, it returns the error if the datanode fails to start,
+// the failed datanode is stopped before return.
+func (cluster *MiniClusterV2) AddDataNode() (_ *grpcdatanode.Server, err error) {
+	cluster.ptmu.Lock()
+	defer cluster.ptmu.Unlock()
+	id := cluster.nextNodeID(typeutil.DataNodeRole, &cluster.dnid)
 	oid := paramtable.GetNodeID()
 	log.Info(fmt.Sprintf("adding extra datanode with id:%d", id))
 	paramtable.SetNodeID(id)
+	defer paramtable.SetNodeID(oid)
 	node, err := grpcdatanode.NewServer(context.TODO(), cluster.factory)
 	if err != nil {
-		return nil
+		return nil, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("datanode %d failed to start: %v", id, r)
+		}
+		if err != nil {
+			node.Stop()
+		}
+	}()
+	if err := runComponentE(node); err != nil {
+		return nil, err
 	}
-	runComponent(node)
-	paramtable.SetNodeID(oid)
 
-	req := &milvuspb.GetComponentStatesRequest{}
-	resp, err := node.GetComponentStates(context.TODO(), req)
-	if err != nil {
-		return nil
+	resp, err := node.GetComponentStates(context.TODO(), &milvuspb.GetComponentStatesRequest{})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, errors.Wrapf(err, "failed to get component states of datanode %d", id)
 	}
 	log.Info(fmt.Sprintf("datanode %d ComponentStates:%v", id, resp))
 	cluster.datanodes = append(cluster.datanodes, node)
-	return node
+	return node, nil
 }
 
-func (cluster *MiniClusterV2) AddStreamingNode() {
+// RestartDataNode stops the extra datanode added by AddDataNode and brings up a new one with the same node id in its place,
+// so that datacoord sees the same node rejoining and may assign the channels it watched back to it.
+// The channels are released to datacoord once the datanode stops, and they are not guaranteed to wait for it
+// unless the assign func of WithChannelAssignment keeps them unassigned by KeepUnassigned in the meantime.
+// When the streaming service is enabled, the channels are owned by the streaming nodes assigned by streamingcoord instead,
+// so restarting a datanode leaves the channels untouched and only affects the tasks run by it, e.g. compaction and import.
+func (cluster *MiniClusterV2) RestartDataNode(server *grpcdatanode.Server) error {
 	cluster.ptmu.Lock()
 	defer cluster.ptmu.Unlock()
+	idx := slices.Index(cluster.datanodes, server)
+	if idx < 0 {
+		return errors.New("datanode to restart is not an extra datanode of the cluster")
+	}
+	states, err := server.GetComponentStates(context.TODO(), &milvuspb.GetComponentStatesRequest{})
+	if err := merr.CheckRPCCall(states, err); err != nil {
+		return errors.Wrap(err, "failed to get node id of datanode to restart")
+	}
+	id := states.GetState().GetNodeID()
+	if err := server.Stop(); err != nil {
+		return errors.Wrapf(err, "failed to stop datanode %d", id)
+	}
+	log.Info(fmt.Sprintf("restarting extra datanode with id:%d", id))
 
+	oid := paramtable.GetNodeID()
+	paramtable.SetNodeID(id)
+	defer paramtable.SetNodeID(oid)
+	node, err := grpcdatanode.NewServer(context.TODO(), cluster.factory)
+	if err != nil {
+		return err
+	}
+	if err := runComponentE(node); err != nil {
+		return err
+	}
+	cluster.datanodes[idx] = node
+	return nil
+}
+
+func (cluster *MiniClusterV2) AddStreamingNode() {
+	cluster.ptmu.Lock()
+	defer cluster.ptmu.Unlock()
+	id := cluster.nextNodeID(typeutil.StreamingNodeRole, &cluster.snid)
+	oid := paramtable.GetNodeID()
+	log.Info(fmt.Sprintf("adding extra streamingnode with id:%d", id))
+	paramtable.SetNodeID(id)
 	node, err := streamingnode.NewServer(context.TODO(), cluster.factory)
 	if err != nil {
 		panic(err)
 	}
 	runComponent(node)
+	paramtable.SetNodeID(oid)
 	cluster.streamingnodes = append(cluster.streamingnodes, node)
 }
 
+// AddProxy brings up an extra proxy listening on fresh ports, the ports of the main proxy are kept in params.
+// It returns the error if the proxy fails to start, the failed proxy is stopped before return.
+func (cluster *MiniClusterV2) AddProxy() (_ *grpcproxy.Server, err error) {
+	cluster.ptmu.Lock()
+	defer cluster.ptmu.Unlock()
+	ports, err := cluster.GetAvailablePorts(2)
+	if err != nil {
+		return nil, err
+	}
+	oldPort := params.ProxyGrpcServerCfg.Port.GetValue()
+	oldInternalPort := params.ProxyGrpcServerCfg.InternalPort.GetValue()
+	params.Save(params.ProxyGrpcServerCfg.Port.Key, fmt.Sprint(ports[0]))
+	params.Save(params.ProxyGrpcServerCfg.InternalPort.Key, fmt.Sprint(ports[1]))
+	defer func() {
+		params.Save(params.ProxyGrpcServerCfg.Port.Key, oldPort)
+		params.Save(params.ProxyGrpcServerCfg.InternalPort.Key, oldInternalPort)
+	}()
+
+	id := cluster.nextNodeID(typeutil.ProxyRole, &cluster.pid)
+	oid := paramtable.GetNodeID()
+	log.Info(fmt.Sprintf("adding extra proxy with id:%d on port:%d", id, ports[0]))
+	paramtable.SetNodeID(id)
+	defer paramtable.SetNodeID(oid)
+	node, err := grpcproxy.NewServer(context.TODO(), cluster.factory)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("proxy %d failed to start: %v", id, r)
+		}
+		if err != nil {
+			node.Stop()
+		}
+	}()
+	if err := runComponentE(node); err != nil {
+		return nil, err
+	}
+	cluster.proxies = append(cluster.proxies, node)
+	return node, nil
+}
+
 func (cluster *MiniClusterV2) Start() error {
 	log.Info("mini cluster start")
-	runComponent(cluster.RootCoord)
-	runComponent(cluster.DataCoord)
-	runComponent(cluster.QueryCoord)
-	runComponent(cluster.DataNode)
-	runComponent(cluster.QueryNode)
-	runComponent(cluster.Proxy)
+	if err := cluster.startProfiling(); err != nil {
+		return err
+	}
+	if err := cluster.startComponents(); err != nil {
+		return err
+	}
+
+	if err := cluster.dialProxy(); err != nil {
+		return err
+	}
+	log.Info("minicluster started")
+	return nil
+}
+
+// dialProxy connects MilvusClient to the proxy, the previous connection, if any, is closed first.
+func (cluster *MiniClusterV2) dialProxy() error {
+	if cluster.clientConn != nil {
+		cluster.clientConn.Close()
+		cluster.clientConn = nil
+	}
+	port := params.ProxyGrpcServerCfg.Port.GetAsInt()
+	conn, err := grpc.DialContext(cluster.ctx, fmt.Sprintf("localhost:%d", port), cluster.getGrpcDialOpt()...)
+	if err != nil {
+		return err
+	}
+	cluster.clientConn = conn
+	cluster.MilvusClient = milvuspb.NewMilvusServiceClient(conn)
+	return nil
+}
+
+// startComponents runs the servers of all components and waits until the cluster is healthy.
+// startComponents brings up the coordinators at the same time, then the nodes and proxy at the same time once all coordinators are up.
+func (cluster *MiniClusterV2) startComponents() error {
+	if err := runComponentsE(cluster.RootCoord, cluster.DataCoord, cluster.QueryCoord); err != nil {
+		return errors.Wrap(err, "failed to start coordinators")
+	}
+	if err := runComponentsE(cluster.DataNode, cluster.QueryNode, cluster.Proxy); err != nil {
+		return errors.Wrap(err, "failed to start nodes")
+	}
 
 	ctx2, cancel := context.WithTimeout(context.Background(), time.Second*120)
 	defer cancel()
-	healthy := false
-	for !healthy {
-		checkHealthResp, _ := cluster.Proxy.CheckHealth(ctx2, &milvuspb.CheckHealthRequest{})
-		healthy = checkHealthResp.IsHealthy
-		time.Sleep(time.Second * 1)
-	}
-	if !healthy {
-		return errors.New("minicluster is not healthy after 120s")
+	for {
+		checkHealthResp, err := cluster.Proxy.CheckHealth(ctx2, &milvuspb.CheckHealthRequest{})
+		if checkHealthResp.GetIsHealthy() {
+			break
+		}
+		select {
+		case <-ctx2.Done():
+			return errors.Wrapf(ctx2.Err(), "minicluster is not healthy after 120s, last error: %v", err)
+		case <-time.After(time.Second):
+		}
 	}
 
 	if streamingutil.IsStreamingServiceEnabled() {
 		paramtable.SetLocalComponentEnabled(typeutil.StreamingNodeRole)
 		runComponent(cluster.StreamingNode)
+		for i := 1; i < cluster.streamingNodeNum; i++ {
+			cluster.AddStreamingNode()
+		}
+		if err := cluster.waitForStreamingNodesHealthy(ctx2); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	port := params.ProxyGrpcServerCfg.Port.GetAsInt()
-	var err error
-	cluster.clientConn, err = grpc.DialContext(cluster.ctx, fmt.Sprintf("localhost:%d", port), getGrpcDialOpt()...)
+// newRootCoord creates the rootcoord server, whose tso allocator reads the clock shifted by ApplyClockJump.
+func (cluster *MiniClusterV2) newRootCoord(ctx context.Context) (*grpcrootcoord.Server, error) {
+	server, err := grpcrootcoord.NewServer(ctx, cluster.factory)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	cluster.MilvusClient = milvuspb.NewMilvusServiceClient(cluster.clientConn)
-	log.Info("minicluster started")
-	return nil
+	server.GetRootCoord().(*rootcoord.Core).SetTSOClock(func() time.Time {
+		return time.Now().Add(cluster.clockOffset.Load())
+	})
+	return server, nil
 }
 
 func (cluster *MiniClusterV2) StopRootCoord() {
@@ -383,7 +975,7 @@ func (cluster *MiniClusterV2) StartRootCoord() {
 	if cluster.RootCoord == nil {
 		coordclient.ResetRootCoordRegistration()
 		var err error
-		if cluster.RootCoord, err = grpcrootcoord.NewServer(cluster.ctx, cluster.factory); err != nil {
+		if cluster.RootCoord, err = cluster.newRootCoord(cluster.ctx); err != nil {
 			panic(err)
 		}
 		runComponent(cluster.RootCoord)
@@ -401,7 +993,7 @@ func (cluster *MiniClusterV2) StartDataCoord() {
 	if cluster.DataCoord == nil {
 		coordclient.ResetRootCoordRegistration()
 		var err error
-		if cluster.DataCoord, err = grpcdatacoord.NewServer(cluster.ctx, cluster.factory); err != nil {
+		if cluster.DataCoord, err = grpcdatacoord.NewServer(cluster.ctx, cluster.factory, cluster.dataCoordOpts...); err != nil {
 			panic(err)
 		}
 		runComponent(cluster.DataCoord)
@@ -426,8 +1018,49 @@ func (cluster *MiniClusterV2) StartQueryCoord() {
 	}
 }
 
-func getGrpcDialOpt() []grpc.DialOption {
-	return []grpc.DialOption{
+func (cluster *MiniClusterV2) StopProxy() {
+	if err := cluster.Proxy.Stop(); err != nil {
+		panic(err)
+	}
+	cluster.Proxy = nil
+}
+
+// StartProxy brings up a new proxy on the same port after StopProxy, and re-dials MilvusClient to it.
+func (cluster *MiniClusterV2) StartProxy() {
+	if cluster.Proxy == nil {
+		var err error
+		if cluster.Proxy, err = grpcproxy.NewServer(cluster.ctx, cluster.factory); err != nil {
+			panic(err)
+		}
+		runComponent(cluster.Proxy)
+		if err := cluster.dialProxy(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// getGrpcDialOpt returns the dial options of the clients to proxy,
+// the extra interceptors set by WithClientInterceptors are chained before the default ones,
+// and the dial options set by WithClientDialOptions are applied after them.
+// The credential of root is attached to every call once WithAuthEnabled.
+func (cluster *MiniClusterV2) getGrpcDialOpt() []grpc.DialOption {
+	opts := cluster.getUnauthenticatedGrpcDialOpt()
+	if cluster.rootPassword != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(newRootCredential(cluster.rootPassword)))
+	}
+	return opts
+}
+
+// getUnauthenticatedGrpcDialOpt returns the dial options of the clients to proxy without any credential.
+func (cluster *MiniClusterV2) getUnauthenticatedGrpcDialOpt() []grpc.DialOption {
+	unaryInterceptors := append(slices.Clone(cluster.unaryInterceptors), grpc_retry.UnaryClientInterceptor(
+		grpc_retry.WithMax(6),
+		grpc_retry.WithBackoff(func(attempt uint) time.Duration {
+			return 60 * time.Millisecond * time.Duration(math.Pow(3, float64(attempt)))
+		}),
+		grpc_retry.WithCodes(codes.Unavailable, codes.ResourceExhausted)),
+	)
+	opts := []grpc.DialOption{
 		grpc.WithBlock(),
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
 			Time:                5 * time.Second,
@@ -443,43 +1076,80 @@ func getGrpcDialOpt() []grpc.DialOption {
 			},
 			MinConnectTimeout: 3 * time.Second,
 		}),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithChainUnaryInterceptor(grpc_retry.UnaryClientInterceptor(
-			grpc_retry.WithMax(6),
-			grpc_retry.WithBackoff(func(attempt uint) time.Duration {
-				return 60 * time.Millisecond * time.Duration(math.Pow(3, float64(attempt)))
-			}),
-			grpc_retry.WithCodes(codes.Unavailable, codes.ResourceExhausted)),
-		),
+		grpc.WithTransportCredentials(cluster.getTransportCredentials()),
+		grpc.WithChainUnaryInterceptor(unaryInterceptors...),
+		grpc.WithChainStreamInterceptor(cluster.streamInterceptors...),
 	}
+	return append(opts, cluster.dialOpts...)
 }
 
+// Stop stops the cluster with the graceful stop timeout of common.gracefulStopTimeout for each component.
 func (cluster *MiniClusterV2) Stop() error {
-	log.Info("mini cluster stop")
+	return cluster.StopWithTimeout(params.CommonCfg.GracefulStopTimeout.GetAsDuration(time.Second))
+}
+
+// StopWithTimeout stops the components one after another, coordinators first, then proxy and nodes,
+// and cleans up the data of the cluster. A component that doesn't stop within d is left behind
+// so that a deadlocked one can't hang the teardown, the returned error lists every component failing to stop.
+func (cluster *MiniClusterV2) StopWithTimeout(d time.Duration) error {
+	log.Info("mini cluster stop", zap.Duration("timeout", d))
+	cluster.SetStorageReadOnly(false)
+	if err := cluster.stopProfiling(); err != nil {
+		log.Warn("fail to write profiles", zap.String("dir", cluster.profileDir), zap.Error(err))
+	}
 	if cluster.clientConn != nil {
 		cluster.clientConn.Close()
 	}
+	CloseConnections(cluster.unauthConns)
+	cluster.unauthConns = nil
+
+	var errs []error
+	stop := func(name string, c stoppable) {
+		if err := stopComponent(name, c, d); err != nil {
+			log.Warn("mini cluster component failed to stop", zap.String("component", name), zap.Error(err))
+			errs = append(errs, err)
+			return
+		}
+		log.Info("mini cluster " + name + " stopped")
+	}
 	if cluster.RootCoord != nil {
-		cluster.RootCoord.Stop()
-		log.Info("mini cluster rootCoord stopped")
+		stop("rootCoord", cluster.RootCoord)
 	}
-
 	if cluster.DataCoord != nil {
-		cluster.DataCoord.Stop()
-		log.Info("mini cluster dataCoord stopped")
+		stop("dataCoord", cluster.DataCoord)
 	}
 	if cluster.QueryCoord != nil {
-		cluster.QueryCoord.Stop()
-		log.Info("mini cluster queryCoord stopped")
+		stop("queryCoord", cluster.QueryCoord)
 	}
 	if cluster.Proxy != nil {
-		cluster.Proxy.Stop()
-		log.Info("mini cluster proxy stopped")
+		stop("proxy", cluster.Proxy)
 	}
-
-	cluster.StopAllDataNodes()
-	cluster.StopAllStreamingNodes()
-	cluster.StopAllQueryNodes()
+	for i, node := range cluster.proxies {
+		stop(fmt.Sprintf("extra proxy %d", i), node)
+	}
+	cluster.proxies = nil
+	if cluster.DataNode != nil {
+		stop("main dataNode", cluster.DataNode)
+	}
+	for i, node := range cluster.datanodes {
+		stop(fmt.Sprintf("extra dataNode %d", i), node)
+	}
+	cluster.datanodes = nil
+	if cluster.StreamingNode != nil {
+		stop("main streamingnode", cluster.StreamingNode)
+	}
+	for i, node := range cluster.streamingnodes {
+		stop(fmt.Sprintf("extra streamingnode %d", i), node)
+	}
+	cluster.streamingnodes = nil
+	if cluster.QueryNode != nil {
+		stop("main queryNode", cluster.QueryNode)
+	}
+	for i, node := range cluster.querynodes {
+		stop(fmt.Sprintf("extra queryNode %d", i), node)
+	}
+	cluster.querynodes = nil
+	cluster.clearSearchFailures()
 
 	cluster.EtcdCli.KV.Delete(cluster.ctx, params.EtcdCfg.RootPath.GetValue(), clientv3.WithPrefix())
 	defer cluster.EtcdCli.Close()
@@ -492,21 +1162,159 @@ func (cluster *MiniClusterV2) Stop() error {
 			cluster.ChunkManager = chunkManager
 		}
 	}
-	cluster.ChunkManager.RemoveWithPrefix(cluster.ctx, cluster.ChunkManager.RootPath())
+	if cluster.ChunkManager != nil {
+		if err := cluster.ChunkManager.RemoveWithPrefix(cluster.ctx, cluster.ChunkManager.RootPath()); err != nil {
+			log.Warn("fail to clean test data", zap.Error(err))
+		}
+	}
 	streaming.Release()
-	return nil
+	if cluster.etcdProxy != nil {
+		cluster.etcdProxy.Close()
+	}
+	if cluster.embedEtcd {
+		cluster.stopEmbeddedEtcd()
+	}
+	cluster.releasePorts()
+	if cluster.rootPassword != "" {
+		params.Reset(params.CommonCfg.AuthorizationEnabled.Key)
+		params.Reset(params.CommonCfg.DefaultRootPassword.Key)
+	}
+	if cluster.tlsCreds != nil {
+		params.Reset(params.ProxyGrpcServerCfg.TLSMode.Key)
+		params.Reset(params.ProxyGrpcServerCfg.ServerPemPath.Key)
+		params.Reset(params.ProxyGrpcServerCfg.ServerKeyPath.Key)
+		params.Reset(params.ProxyGrpcServerCfg.CaPemPath.Key)
+		params.Reset(params.HTTPCfg.Enabled.Key)
+	}
+	if cluster.restoreStreamingService != nil {
+		cluster.restoreStreamingService()
+		cluster.restoreStreamingService = nil
+	}
+	return merr.Combine(errs...)
+}
+
+// setStreamingService sets the environment of the streaming service for WithStreamingService,
+// and keeps the previous one along with whether the streaming node is enabled locally to be restored on Stop.
+func (cluster *MiniClusterV2) setStreamingService(enabled bool) {
+	prev, ok := os.LookupEnv(streamingutil.MilvusStreamingServiceEnabled)
+	localEnabled := paramtable.IsLocalComponentEnabled(typeutil.StreamingNodeRole)
+	cluster.restoreStreamingService = func() {
+		if ok {
+			os.Setenv(streamingutil.MilvusStreamingServiceEnabled, prev)
+		} else {
+			os.Unsetenv(streamingutil.MilvusStreamingServiceEnabled)
+		}
+		if !localEnabled {
+			paramtable.SetLocalComponentDisabled(typeutil.StreamingNodeRole)
+		}
+	}
+	if !enabled {
+		streamingutil.UnsetStreamingServiceEnabled()
+		return
+	}
+	streamingutil.SetStreamingServiceEnabled()
+	// the local wal manager can only be registered with the streaming node enabled locally
+	paramtable.SetLocalComponentEnabled(typeutil.StreamingNodeRole)
+}
+
+// startEmbeddedEtcd starts the embedded etcd server for WithEmbeddedEtcd with its data under localStorage.path.
+func (cluster *MiniClusterV2) startEmbeddedEtcd() error {
+	if cluster.proxyEtcd {
+		return errors.New("etcd latency can't be injected into embedded etcd")
+	}
+	if etcd.HasServer() {
+		return errors.New("embedded etcd has been started by another cluster of the process")
+	}
+	etcdConfig := &params.EtcdCfg
+	dataDir := path.Join(params.LocalStorageCfg.Path.GetValue(), "etcd")
+	params.Save(etcdConfig.UseEmbedEtcd.Key, "true")
+	params.Save(etcdConfig.DataDir.Key, dataDir)
+	return etcd.InitEtcdServer(true, etcdConfig.ConfigPath.GetValue(), dataDir,
+		etcdConfig.EtcdLogPath.GetValue(), etcdConfig.EtcdLogLevel.GetValue())
+}
+
+// stopEmbeddedEtcd stops the embedded etcd server and removes its data,
+// the params are reset so that later clusters of the process go back to etcd.endpoints.
+func (cluster *MiniClusterV2) stopEmbeddedEtcd() {
+	etcd.StopEtcdServer()
+	dataDir := params.EtcdCfg.DataDir.GetValue()
+	if err := os.RemoveAll(dataDir); err != nil {
+		log.Warn("fail to remove embedded etcd data", zap.String("dir", dataDir), zap.Error(err))
+	}
+	params.Reset(params.EtcdCfg.UseEmbedEtcd.Key)
+	params.Reset(params.EtcdCfg.DataDir.Key)
+}
+
+// stopComponent stops the component, and gives up waiting for it once d elapses.
+func stopComponent(name string, c stoppable, d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Stop()
+	}()
+	select {
+	case err := <-done:
+		return errors.Wrapf(err, "%s failed to stop", name)
+	case <-ctx.Done():
+		return errors.Wrapf(ctx.Err(), "%s not stopped in %s", name, d)
+	}
 }
 
 func (cluster *MiniClusterV2) GetAllQueryNodes() []*grpcquerynode.Server {
 	ret := make([]*grpcquerynode.Server, 0)
-	ret = append(ret, cluster.QueryNode)
+	if cluster.QueryNode != nil {
+		ret = append(ret, cluster.QueryNode)
+	}
 	ret = append(ret, cluster.querynodes...)
 	return ret
 }
 
+// untrackQueryNode forgets the stopped querynode, so that it's neither reported nor stopped again on Stop.
+func (cluster *MiniClusterV2) untrackQueryNode(node *grpcquerynode.Server) {
+	cluster.ptmu.Lock()
+	defer cluster.ptmu.Unlock()
+	if cluster.QueryNode == node {
+		cluster.QueryNode = nil
+		return
+	}
+	for i, n := range cluster.querynodes {
+		if n == node {
+			cluster.querynodes = append(cluster.querynodes[:i], cluster.querynodes[i+1:]...)
+			return
+		}
+	}
+}
+
+// WaitForQueryNodeNum waits until exactly expected querynodes are registered in etcd.
+func (cluster *MiniClusterV2) WaitForQueryNodeNum(ctx context.Context, expected int) error {
+	for {
+		sessions, err := cluster.MetaWatcher.ShowSessions()
+		if err != nil {
+			return err
+		}
+		actual := 0
+		for _, session := range sessions {
+			if session.ServerName == typeutil.QueryNodeRole {
+				actual++
+			}
+		}
+		if actual == expected {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "%d querynodes registered, expected %d", actual, expected)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
 func (cluster *MiniClusterV2) StopAllQueryNodes() {
-	cluster.QueryNode.Stop()
-	log.Info("mini cluster main queryNode stopped")
+	if cluster.QueryNode != nil {
+		cluster.QueryNode.Stop()
+		log.Info("mini cluster main queryNode stopped")
+	}
 	numExtraQN := len(cluster.querynodes)
 	for _, node := range cluster.querynodes {
 		node.Stop()
@@ -515,9 +1323,18 @@ func (cluster *MiniClusterV2) StopAllQueryNodes() {
 	log.Info(fmt.Sprintf("mini cluster stopped %d extra querynode", numExtraQN))
 }
 
+func (cluster *MiniClusterV2) GetAllDataNodes() []*grpcdatanode.Server {
+	ret := make([]*grpcdatanode.Server, 0)
+	ret = append(ret, cluster.DataNode)
+	ret = append(ret, cluster.datanodes...)
+	return ret
+}
+
 func (cluster *MiniClusterV2) StopAllDataNodes() {
-	cluster.DataNode.Stop()
-	log.Info("mini cluster main dataNode stopped")
+	if cluster.DataNode != nil {
+		cluster.DataNode.Stop()
+		log.Info("mini cluster main dataNode stopped")
+	}
 	numExtraDN := len(cluster.datanodes)
 	for _, node := range cluster.datanodes {
 		node.Stop()
@@ -526,6 +1343,97 @@ func (cluster *MiniClusterV2) StopAllDataNodes() {
 	log.Info(fmt.Sprintf("mini cluster stopped %d extra datanode", numExtraDN))
 }
 
+func (cluster *MiniClusterV2) GetAllProxies() []*grpcproxy.Server {
+	ret := make([]*grpcproxy.Server, 0)
+	if cluster.Proxy != nil {
+		ret = append(ret, cluster.Proxy)
+	}
+	ret = append(ret, cluster.proxies...)
+	return ret
+}
+
+// StopAllProxies stops the main proxy and the extra ones added by AddProxy.
+func (cluster *MiniClusterV2) StopAllProxies() {
+	if cluster.Proxy != nil {
+		cluster.Proxy.Stop()
+		cluster.Proxy = nil
+		log.Info("mini cluster main proxy stopped")
+	}
+	for _, node := range cluster.proxies {
+		node.Stop()
+	}
+	log.Info(fmt.Sprintf("mini cluster stopped %d extra proxies", len(cluster.proxies)))
+	cluster.proxies = nil
+}
+
+func (cluster *MiniClusterV2) GetAllStreamingNodes() []*streamingnode.Server {
+	ret := make([]*streamingnode.Server, 0)
+	if cluster.StreamingNode != nil {
+		ret = append(ret, cluster.StreamingNode)
+	}
+	ret = append(ret, cluster.streamingnodes...)
+	return ret
+}
+
+// untrackStreamingNode forgets the stopped streaming node, so that it's not stopped again on Stop.
+func (cluster *MiniClusterV2) untrackStreamingNode(node *streamingnode.Server) {
+	cluster.ptmu.Lock()
+	defer cluster.ptmu.Unlock()
+	if cluster.StreamingNode == node {
+		cluster.StreamingNode = nil
+		return
+	}
+	for i, n := range cluster.streamingnodes {
+		if n == node {
+			cluster.streamingnodes = append(cluster.streamingnodes[:i], cluster.streamingnodes[i+1:]...)
+			return
+		}
+	}
+}
+
+func (cluster *MiniClusterV2) waitForStreamingNodesHealthy(ctx context.Context) error {
+	for _, node := range cluster.GetAllStreamingNodes() {
+		for node.Health(ctx) != commonpb.StateCode_Healthy {
+			select {
+			case <-ctx.Done():
+				return errors.New("streamingnode is not healthy after 120s")
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	}
+	return nil
+}
+
+// RestartStreamingNode stops the main streaming node and brings up a new one in its place,
+// it blocks until the new one is healthy, so that the recovery of the wals it served can be tested.
+// It fails if the streaming service is not enabled.
+func (cluster *MiniClusterV2) RestartStreamingNode() error {
+	if !streamingutil.IsStreamingServiceEnabled() {
+		return errors.New("streaming service is not enabled, no streaming node to restart")
+	}
+	if cluster.StreamingNode != nil {
+		if err := cluster.StreamingNode.Stop(); err != nil {
+			return errors.Wrap(err, "failed to stop streamingnode")
+		}
+		cluster.StreamingNode = nil
+	}
+	log.Info("restarting main streamingnode")
+
+	node, err := streamingnode.NewServer(cluster.ctx, cluster.factory)
+	if err != nil {
+		return err
+	}
+	paramtable.SetLocalComponentEnabled(typeutil.StreamingNodeRole)
+	if err := runComponentE(node); err != nil {
+		return err
+	}
+	cluster.StreamingNode = node
+
+	ctx, cancel := context.WithTimeout(cluster.ctx, time.Second*120)
+	defer cancel()
+	return cluster.waitForStreamingNodesHealthy(ctx)
+}
+
 func (cluster *MiniClusterV2) StopAllStreamingNodes() {
 	if cluster.StreamingNode != nil {
 		cluster.StreamingNode.Stop()
@@ -546,6 +1454,15 @@ func (cluster *MiniClusterV2) GetFactory() dependency.Factory {
 	return cluster.factory
 }
 
+// GetClientConn returns the connection MilvusClient dials proxy with, to create stubs of other services served by proxy,
+// the interceptors of WithClientInterceptors apply to them as well. The connection is replaced once proxy restarts.
+func (cluster *MiniClusterV2) GetClientConn() (*grpc.ClientConn, error) {
+	if cluster.clientConn == nil {
+		return nil, errors.New("mini cluster not started, no connection to proxy")
+	}
+	return cluster.clientConn, nil
+}
+
 func (cluster *MiniClusterV2) GetAvailablePorts(n int) ([]int, error) {
 	ports := typeutil.NewSet[int]()
 	for ports.Len() < n {
@@ -558,21 +1475,126 @@ func (cluster *MiniClusterV2) GetAvailablePorts(n int) ([]int, error) {
 	return ports.Collect(), nil
 }
 
-func (cluster *MiniClusterV2) GetAvailablePort() (int, error) {
-	address, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("%s:0", "0.0.0.0"))
-	if err != nil {
-		return 0, err
+// allocatePorts returns the port of each role, either the fixed one by WithPorts or a randomly allocated one.
+func (cluster *MiniClusterV2) allocatePorts(roles ...string) (map[string]int, error) {
+	ports := make(map[string]int, len(roles))
+	supported := typeutil.NewSet(roles...)
+	used := typeutil.NewSet[int]()
+	for role, port := range cluster.fixedPorts {
+		if !supported.Contain(role) {
+			return nil, errors.Newf("fixed port %d of role %s is not supported", port, role)
+		}
+		if used.Contain(port) {
+			return nil, errors.Newf("fixed port %d is shared by more than one role", port)
+		}
+		if err := cluster.leaseFixedPort(port); err != nil {
+			return nil, errors.Wrapf(err, "fixed port %d of role %s is not free", port, role)
+		}
+		ports[role] = port
+		used.Insert(port)
+	}
+	for _, role := range roles {
+		if _, ok := ports[role]; ok {
+			continue
+		}
+		for {
+			port, err := cluster.GetAvailablePort()
+			if err != nil {
+				return nil, err
+			}
+			if !used.Contain(port) {
+				ports[role] = port
+				used.Insert(port)
+				break
+			}
+		}
 	}
-	listener, err := net.ListenTCP("tcp", address)
+	return ports, nil
+}
+
+func checkPortFree(port int) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", port))
 	if err != nil {
-		return 0, err
+		return err
+	}
+	return listener.Close()
+}
+
+// portLeases tracks the ports leased to the clusters of the process.
+var portLeases = struct {
+	mu     sync.Mutex
+	leased typeutil.Set[int]
+}{leased: typeutil.NewSet[int]()}
+
+// GetAvailablePort leases a free port to the cluster until Stop, from the range of WithPortRange if set,
+// or picked by the OS otherwise. A port leased to another cluster of the process is never returned.
+func (cluster *MiniClusterV2) GetAvailablePort() (int, error) {
+	portLeases.mu.Lock()
+	defer portLeases.mu.Unlock()
+	if cluster.portRangeEnd > 0 {
+		for port := cluster.portRangeStart; port < cluster.portRangeEnd; port++ {
+			if portLeases.leased.Contain(port) || checkPortFree(port) != nil {
+				continue
+			}
+			cluster.leasePort(port)
+			return port, nil
+		}
+		return 0, errors.Newf("no free port in range [%d, %d)", cluster.portRangeStart, cluster.portRangeEnd)
+	}
+	for {
+		address, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("%s:0", "0.0.0.0"))
+		if err != nil {
+			return 0, err
+		}
+		listener, err := net.ListenTCP("tcp", address)
+		if err != nil {
+			return 0, err
+		}
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+		if !portLeases.leased.Contain(port) {
+			cluster.leasePort(port)
+			return port, nil
+		}
 	}
-	defer listener.Close()
-	return listener.Addr().(*net.TCPAddr).Port, nil
 }
 
-func InitReportExtension() *ReportChanExtension {
-	e := NewReportChanExtension()
+// leaseFixedPort leases the port set by WithPorts to the cluster, it fails if the port is not free or leased by another cluster.
+func (cluster *MiniClusterV2) leaseFixedPort(port int) error {
+	portLeases.mu.Lock()
+	defer portLeases.mu.Unlock()
+	if portLeases.leased.Contain(port) {
+		return errors.Newf("port %d is leased by another cluster", port)
+	}
+	if err := checkPortFree(port); err != nil {
+		return err
+	}
+	cluster.leasePort(port)
+	return nil
+}
+
+// leasePort shall be called with portLeases.mu held.
+func (cluster *MiniClusterV2) leasePort(port int) {
+	portLeases.leased.Insert(port)
+	cluster.leasedPorts = append(cluster.leasedPorts, port)
+}
+
+// releasePorts returns all ports leased to the cluster.
+func (cluster *MiniClusterV2) releasePorts() {
+	portLeases.mu.Lock()
+	defer portLeases.mu.Unlock()
+	portLeases.leased.Remove(cluster.leasedPorts...)
+	cluster.leasedPorts = nil
+}
+
+// InitReportExtension sets up the extension receiving the hook reports of the process,
+// the report channel is buffered by bufferSize if given, and unbuffered otherwise.
+func InitReportExtension(bufferSize ...int) *ReportChanExtension {
+	size := 0
+	if len(bufferSize) > 0 {
+		size = bufferSize[0]
+	}
+	e := NewReportChanExtensionWithBuffer(size)
 	hookutil.InitOnceHook()
 	hookutil.SetTestExtension(e)
 	return e
@@ -580,18 +1602,52 @@ func InitReportExtension() *ReportChanExtension {
 
 type ReportChanExtension struct {
 	reportChan chan any
+	dropped    atomic.Int64
+
+	mu          sync.Mutex
+	subscribers map[int64]*reportSubscriber
+	nextSubID   int64
+}
+
+// reportSubscriberBufferSize is the number of infos buffered for a subscriber, infos beyond are dropped.
+const reportSubscriberBufferSize = 1024
+
+type reportSubscriber struct {
+	predicate func(any) bool
+	ch        chan any
 }
 
 func NewReportChanExtension() *ReportChanExtension {
+	return NewReportChanExtensionWithBuffer(0)
+}
+
+// NewReportChanExtensionWithBuffer returns the extension whose report channel buffers up to size reports,
+// so that reports are kept even if no one is receiving at the moment, and can be counted afterwards.
+func NewReportChanExtensionWithBuffer(size int) *ReportChanExtension {
 	return &ReportChanExtension{
-		reportChan: make(chan any),
+		reportChan:  make(chan any, size),
+		subscribers: make(map[int64]*reportSubscriber),
 	}
 }
 
 func (r *ReportChanExtension) Report(info any) int {
+	r.mu.Lock()
+	for _, sub := range r.subscribers {
+		if !sub.predicate(info) {
+			continue
+		}
+		// a slow subscriber shall never block the reporting
+		select {
+		case sub.ch <- info:
+		default:
+		}
+	}
+	r.mu.Unlock()
+
 	select {
 	case r.reportChan <- info:
 	default:
+		r.dropped.Inc()
 	}
 	return 1
 }
@@ -604,16 +1660,71 @@ func (r *ReportChanExtension) GetReportChan() <-chan any {
 	return r.reportChan
 }
 
+// Drained returns how many reports have been dropped from the report channel since it's full,
+// or no one is receiving for an unbuffered one.
+func (r *ReportChanExtension) Drained() int64 {
+	return r.dropped.Load()
+}
+
+// Subscribe returns a channel receiving the infos reported from now on which satisfy predicate,
+// e.g. ReportOfOpType(hookutil.OpTypeQuery) for the reports of queries only.
+// Up to reportSubscriberBufferSize infos are buffered for the subscriber, the rest are dropped if it doesn't keep up.
+// The channel is closed by cancel, which shall be called once the subscriber is done.
+func (r *ReportChanExtension) Subscribe(predicate func(any) bool) (<-chan any, func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.nextSubID
+	r.nextSubID++
+	sub := &reportSubscriber{
+		predicate: predicate,
+		ch:        make(chan any, reportSubscriberBufferSize),
+	}
+	r.subscribers[id] = sub
+	cancel := func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if _, ok := r.subscribers[id]; ok {
+			delete(r.subscribers, id)
+			close(sub.ch)
+		}
+	}
+	return sub.ch, cancel
+}
+
+// ReportOfOpType returns the predicate of Subscribe which accepts the reports of the operation type, e.g. hookutil.OpTypeQuery.
+func ReportOfOpType(opType string) func(any) bool {
+	return func(info any) bool {
+		reportInfo, ok := info.(map[string]any)
+		return ok && reportInfo[hookutil.OpTypeKey] == opType
+	}
+}
+
 type component interface {
 	Prepare() error
 	Run() error
 }
 
 func runComponent(c component) {
-	if err := c.Prepare(); err != nil {
+	if err := runComponentE(c); err != nil {
 		panic(err)
 	}
-	if err := c.Run(); err != nil {
-		panic(err)
+}
+
+func runComponentE(c component) error {
+	if err := c.Prepare(); err != nil {
+		return err
+	}
+	return c.Run()
+}
+
+// runComponentsE runs the components concurrently and waits until all of them are running,
+// it returns the first error if any of them fails.
+func runComponentsE(cs ...component) error {
+	var g errgroup.Group
+	for _, c := range cs {
+		g.Go(func() error {
+			return runComponentE(c)
+		})
 	}
+	return g.Wait()
 }
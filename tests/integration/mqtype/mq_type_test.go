@@ -0,0 +1,79 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtype
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type MqTypeSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *MqTypeSuite) SetupTest() {
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithMqType("rocksmq"))
+}
+
+func (s *MqTypeSuite) TestRunOnMqType() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+	)
+	collectionName := "TestRunOnMqType" + funcutil.GenRandomStr()
+
+	s.Equal("rocksmq", paramtable.Get().MQCfg.Type.GetValue())
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       1,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+	count, err := c.QueryCount(ctx, collectionName, "")
+	s.NoError(err)
+	s.EqualValues(rowNum, count)
+
+	log.Info("TestRunOnMqType succeed")
+}
+
+func (s *MqTypeSuite) TestUnsupportedMqType() {
+	_, err := integration.StartMiniClusterV2(context.Background(), integration.WithMqType("natsmq"))
+	s.ErrorContains(err, "is not supported")
+
+	log.Info("TestUnsupportedMqType succeed")
+}
+
+func TestMqType(t *testing.T) {
+	suite.Run(t, new(MqTypeSuite))
+}
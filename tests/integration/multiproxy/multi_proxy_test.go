@@ -0,0 +1,102 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multiproxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type MultiProxySuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *MultiProxySuite) TestQueryOnEveryProxy() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim      = 128
+		dbName   = ""
+		rowNum   = 2000
+		proxyNum = 3
+	)
+	collectionName := "TestQueryOnEveryProxy" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	mainPort := paramtable.Get().ProxyGrpcServerCfg.Port.GetValue()
+	for i := 1; i < proxyNum; i++ {
+		_, err := c.AddProxy()
+		s.Require().NoError(err)
+	}
+	// the extra proxies don't take over the port of the main one
+	s.Equal(mainPort, paramtable.Get().ProxyGrpcServerCfg.Port.GetValue())
+	s.Len(c.GetAllProxies(), proxyNum)
+
+	s.Eventually(func() bool {
+		sessions, err := c.MetaWatcher.ShowSessions()
+		if err != nil {
+			return false
+		}
+		proxies := 0
+		for _, session := range sessions {
+			if session.ServerName == typeutil.ProxyRole {
+				proxies++
+			}
+		}
+		return proxies == proxyNum
+	}, time.Minute, time.Second)
+
+	for i, proxy := range c.GetAllProxies() {
+		queryResp, err := proxy.Query(ctx, &milvuspb.QueryRequest{
+			DbName:           dbName,
+			CollectionName:   collectionName,
+			OutputFields:     []string{"count(*)"},
+			ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+		})
+		s.Require().NoError(merr.CheckRPCCall(queryResp, err), "proxy %d", i)
+		s.EqualValues(2*rowNum, queryResp.GetFieldsData()[0].GetScalars().GetLongData().GetData()[0], "proxy %d", i)
+	}
+
+	log.Info("TestQueryOnEveryProxy succeed")
+}
+
+func TestMultiProxy(t *testing.T) {
+	suite.Run(t, new(MultiProxySuite))
+}
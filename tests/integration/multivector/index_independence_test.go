@@ -0,0 +1,170 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multivector
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type IndexIndependenceSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *IndexIndependenceSuite) TestDropIndexOnOneVectorField() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 3000
+		topk   = 10
+	)
+	collectionName := "TestDropIndexOnOneVectorField" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, true,
+		&schemapb.FieldSchema{Name: integration.Int64Field, DataType: schemapb.DataType_Int64, IsPrimaryKey: true, AutoID: true},
+		&schemapb.FieldSchema{Name: integration.FloatVecField, DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: strconv.Itoa(dim)}}},
+		&schemapb.FieldSchema{Name: integration.BinVecField, DataType: schemapb.DataType_BinaryVector, TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: strconv.Itoa(dim)}}},
+	)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldsData: []*schemapb.FieldData{
+			integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim),
+			integration.NewBinaryVectorFieldData(integration.BinVecField, rowNum, dim),
+		},
+		HashKeys: integration.GenerateHashKeys(rowNum),
+		NumRows:  uint32(rowNum),
+	})
+	s.NoError(merr.CheckRPCCall(insertResult, err))
+
+	flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+		DbName:          dbName,
+		CollectionNames: []string{collectionName},
+	})
+	s.NoError(merr.CheckRPCCall(flushResp, err))
+	segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+	s.Require().True(has)
+	flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+	s.Require().True(has)
+	s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+
+	// build indexes on both vector fields
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default_float",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuiltWithIndexName(ctx, collectionName, integration.FloatVecField, "_default_float")
+	createIndexStatus, err = c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.BinVecField,
+		IndexName:      "_default_binary",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissBinIvfFlat, metric.JACCARD),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuiltWithIndexName(ctx, collectionName, integration.BinVecField, "_default_binary")
+
+	descs, err := c.DescribeFieldIndexes(ctx, dbName, collectionName)
+	s.NoError(err)
+	s.Require().Contains(descs, integration.FloatVecField)
+	s.Require().Contains(descs, integration.BinVecField)
+	floatIndexID := descs[integration.FloatVecField].GetIndexID()
+
+	// drop the index of the binary vector field
+	dropIndexStatus, err := c.Proxy.DropIndex(ctx, &milvuspb.DropIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.BinVecField,
+		IndexName:      "_default_binary",
+	})
+	s.NoError(merr.CheckRPCCall(dropIndexStatus, err))
+
+	// the index of the float vector field is untouched
+	descs, err = c.DescribeFieldIndexes(ctx, dbName, collectionName)
+	s.NoError(err)
+	s.NotContains(descs, integration.BinVecField)
+	s.Require().Contains(descs, integration.FloatVecField)
+	s.Equal(floatIndexID, descs[integration.FloatVecField].GetIndexID())
+	s.Equal(commonpb.IndexState_Finished, descs[integration.FloatVecField].GetState())
+	s.Equal(int64(rowNum), descs[integration.FloatVecField].GetIndexedRows())
+
+	// loading all fields is rejected since the binary vector field has no index any more
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.Error(merr.CheckRPCCall(loadStatus, err))
+
+	// while the indexed field could still be loaded and searched
+	loadStatus, err = c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		LoadFields:     []string{integration.Int64Field, integration.FloatVecField},
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	fParams := integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2)
+	fSearchReq := integration.ConstructSearchRequest(dbName, collectionName, "",
+		integration.FloatVecField, schemapb.DataType_FloatVector, nil, metric.L2, fParams, 1, dim, topk, -1)
+	searchResult, err := c.Proxy.Search(ctx, fSearchReq)
+	s.NoError(merr.CheckRPCCall(searchResult, err))
+	s.Equal(int64(topk), searchResult.GetResults().GetTopK())
+
+	// the field without index is not loaded, search on it fails
+	bParams := integration.GetSearchParams(integration.IndexFaissBinIvfFlat, metric.JACCARD)
+	bSearchReq := integration.ConstructSearchRequest(dbName, collectionName, "",
+		integration.BinVecField, schemapb.DataType_BinaryVector, nil, metric.JACCARD, bParams, 1, dim, topk, -1)
+	searchResult, err = c.Proxy.Search(ctx, bSearchReq)
+	s.Error(merr.CheckRPCCall(searchResult, err))
+
+	log.Info("TestDropIndexOnOneVectorField succeed")
+}
+
+func TestIndexIndependence(t *testing.T) {
+	suite.Run(t, new(IndexIndependenceSuite))
+}
@@ -0,0 +1,129 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodeconflict
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type NodeIDConflictSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *NodeIDConflictSuite) SetupSuite() {
+	paramtable.Init()
+	// fail the conflicting registration fast
+	paramtable.Get().Save(paramtable.Get().CommonCfg.SessionRetryTimes.Key, "1")
+
+	s.Require().NoError(s.SetupEmbedEtcd())
+}
+
+func (s *NodeIDConflictSuite) TearDownSuite() {
+	paramtable.Get().Reset(paramtable.Get().CommonCfg.SessionRetryTimes.Key)
+
+	s.MiniClusterSuite.TearDownSuite()
+}
+
+func (s *NodeIDConflictSuite) querynodeSessions() []*sessionutil.SessionRaw {
+	sessions, err := s.Cluster.MetaWatcher.ShowSessions()
+	s.Require().NoError(err)
+	return lo.Filter(sessions, func(session *sessionutil.SessionRaw, _ int) bool {
+		return session.ServerName == typeutil.QueryNodeRole
+	})
+}
+
+func (s *NodeIDConflictSuite) TestDuplicateQueryNodeID() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*3)
+	defer cancel()
+
+	first, err := c.AddQueryNode()
+	s.Require().NoError(err)
+	nodeID := first.GetQueryNode().GetNodeID()
+	before, ok := lo.Find(s.querynodeSessions(), func(session *sessionutil.SessionRaw) bool {
+		return session.ServerID == nodeID
+	})
+	s.Require().True(ok)
+
+	// the second querynode with the same id shall be rejected
+	c.ForceNodeID(typeutil.QueryNodeRole, nodeID)
+	duplicate, err := c.AddQueryNode()
+	s.Error(err)
+	s.Nil(duplicate)
+
+	// and the first one is left untouched
+	states, err := first.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+	s.NoError(merr.CheckRPCCall(states, err))
+	s.Equal(commonpb.StateCode_Healthy, states.GetState().GetStateCode())
+
+	after := lo.Filter(s.querynodeSessions(), func(session *sessionutil.SessionRaw, _ int) bool {
+		return session.ServerID == nodeID
+	})
+	s.Require().Len(after, 1)
+	s.Equal(before.Address, after[0].Address)
+
+	log.Info("TestDuplicateQueryNodeID succeed")
+}
+
+func (s *NodeIDConflictSuite) TestExtraNodeIDRanges() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*3)
+	defer cancel()
+
+	qn, err := c.AddQueryNode()
+	s.Require().NoError(err)
+	dn, err := c.AddDataNode()
+	s.Require().NoError(err)
+	states, err := dn.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+	s.Require().NoError(merr.CheckRPCCall(states, err))
+
+	// extra querynodes and datanodes take ids from disjoint ranges
+	qnID, dnID := qn.GetQueryNode().GetNodeID(), states.GetState().GetNodeID()
+	s.Greater(qnID, int64(10000))
+	s.Less(qnID, int64(20000))
+	s.Greater(dnID, int64(20000))
+	s.Less(dnID, int64(30000))
+
+	sessions, err := c.MetaWatcher.ShowSessions()
+	s.Require().NoError(err)
+	s.True(lo.ContainsBy(sessions, func(session *sessionutil.SessionRaw) bool {
+		return session.ServerName == typeutil.QueryNodeRole && session.ServerID == qnID
+	}))
+	s.True(lo.ContainsBy(sessions, func(session *sessionutil.SessionRaw) bool {
+		return session.ServerName == typeutil.DataNodeRole && session.ServerID == dnID
+	}))
+
+	log.Info("TestExtraNodeIDRanges succeed")
+}
+
+func TestNodeIDConflict(t *testing.T) {
+	suite.Run(t, new(NodeIDConflictSuite))
+}
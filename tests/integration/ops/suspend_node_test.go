@@ -92,7 +92,8 @@ func (s *SuspendNodeTestSuite) TestSuspendNode() {
 
 	qns := make([]*grpcquerynode.Server, 0)
 	for i := 1; i < 3; i++ {
-		qn := s.Cluster.AddQueryNode()
+		qn, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 		qns = append(qns, qn)
 	}
 
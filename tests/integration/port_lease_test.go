@@ -0,0 +1,109 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+type PortLeaseSuite struct {
+	suite.Suite
+}
+
+// allocateConcurrently allocates n ports for each of the clusters at the same time, and returns the ports by cluster.
+func (s *PortLeaseSuite) allocateConcurrently(clusters []*MiniClusterV2, n int) [][]int {
+	ports := make([][]int, len(clusters))
+	errs := make([]error, len(clusters))
+	var wg sync.WaitGroup
+	for i, cluster := range clusters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ports[i], errs[i] = cluster.GetAvailablePorts(n)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		s.Require().NoError(err)
+	}
+	return ports
+}
+
+func (s *PortLeaseSuite) checkNoCollision(ports [][]int) {
+	seen := typeutil.NewSet[int]()
+	for _, clusterPorts := range ports {
+		for _, port := range clusterPorts {
+			s.False(seen.Contain(port), "port %d allocated twice", port)
+			seen.Insert(port)
+		}
+	}
+}
+
+func (s *PortLeaseSuite) TestConcurrentClusters() {
+	const (
+		clusterNum = 5
+		portNum    = 6
+		start      = 41000
+		end        = 41100
+	)
+	// the clusters of a process share the global param table, so they can't all run at once,
+	// while their ports are allocated at the same time just as they would be on start
+	ranged := make([]*MiniClusterV2, 0, clusterNum)
+	random := make([]*MiniClusterV2, 0, clusterNum)
+	for i := 0; i < clusterNum; i++ {
+		cluster := &MiniClusterV2{}
+		WithPortRange(start, end)(cluster)
+		ranged = append(ranged, cluster)
+		random = append(random, &MiniClusterV2{})
+	}
+
+	ports := s.allocateConcurrently(append(ranged, random...), portNum)
+	s.checkNoCollision(ports)
+	for _, clusterPorts := range ports[:clusterNum] {
+		for _, port := range clusterPorts {
+			s.GreaterOrEqual(port, start)
+			s.Less(port, end)
+		}
+	}
+
+	// the ports are no longer leased once released
+	for _, cluster := range append(ranged, random...) {
+		cluster.releasePorts()
+	}
+	for _, clusterPorts := range ports {
+		for _, port := range clusterPorts {
+			s.False(portLeases.leased.Contain(port), "port %d still leased", port)
+		}
+	}
+}
+
+func (s *PortLeaseSuite) TestRangeExhausted() {
+	cluster := &MiniClusterV2{}
+	WithPortRange(41100, 41102)(cluster)
+	defer cluster.releasePorts()
+	_, err := cluster.GetAvailablePorts(3)
+	s.ErrorContains(err, "no free port in range")
+}
+
+func TestPortLease(t *testing.T) {
+	suite.Run(t, new(PortLeaseSuite))
+}
@@ -0,0 +1,72 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profiling
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type ProfilingSuite struct {
+	integration.MiniClusterSuite
+
+	profileDir string
+}
+
+func (s *ProfilingSuite) SetupTest() {
+	s.profileDir = s.T().TempDir()
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithProfiling(s.profileDir))
+}
+
+// TearDownTest stops the cluster first, profiles are only written at Stop.
+func (s *ProfilingSuite) TearDownTest() {
+	s.MiniClusterSuite.TearDownTest()
+
+	for _, file := range []string{integration.CPUProfileFile, integration.HeapProfileFile} {
+		info, err := os.Stat(path.Join(s.profileDir, file))
+		s.NoError(err)
+		if err == nil {
+			s.Positive(info.Size(), "profile %s is empty", file)
+		}
+	}
+}
+
+func (s *ProfilingSuite) TestProfiling() {
+	ctx, cancel := context.WithTimeout(s.Cluster.GetContext(), time.Minute*3)
+	defer cancel()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		CollectionName:   "TestProfiling" + funcutil.GenRandomStr(),
+		ChannelNum:       1,
+		SegmentNum:       1,
+		RowNumPerSegment: 1000,
+		Dim:              128,
+		ReplicaNumber:    1,
+	})
+}
+
+func TestProfiling(t *testing.T) {
+	suite.Run(t, new(ProfilingSuite))
+}
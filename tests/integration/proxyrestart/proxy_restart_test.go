@@ -0,0 +1,90 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxyrestart
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type ProxyRestartSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *ProxyRestartSuite) TestQueryAfterProxyRestart() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 2000
+	)
+	collectionName := "TestQueryAfterProxyRestart" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	c.StopProxy()
+	s.Nil(c.Proxy)
+	c.StartProxy()
+	s.Require().NotNil(c.Proxy)
+
+	// the client is re-dialed to the new proxy
+	s.Eventually(func() bool {
+		queryResp, err := c.MilvusClient.Query(ctx, &milvuspb.QueryRequest{
+			DbName:           dbName,
+			CollectionName:   collectionName,
+			OutputFields:     []string{"count(*)"},
+			ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+		})
+		if err := merr.CheckRPCCall(queryResp, err); err != nil {
+			log.Info("query not served after proxy restart yet", zap.Error(err))
+			return false
+		}
+		return queryResp.GetFieldsData()[0].GetScalars().GetLongData().GetData()[0] == 2*rowNum
+	}, time.Minute, time.Second)
+
+	count, err := c.QueryCount(ctx, collectionName, "")
+	s.NoError(err)
+	s.EqualValues(2*rowNum, count)
+
+	log.Info("TestQueryAfterProxyRestart succeed")
+}
+
+func TestProxyRestart(t *testing.T) {
+	suite.Run(t, new(ProxyRestartSuite))
+}
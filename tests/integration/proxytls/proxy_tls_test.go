@@ -0,0 +1,87 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxytls
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const certDir = "../../../configs/cert"
+
+type ProxyTLSSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *ProxyTLSSuite) SetupTest() {
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithTLS(
+		path.Join(certDir, "server.pem"), path.Join(certDir, "server.key"), path.Join(certDir, "ca.pem")))
+}
+
+func (s *ProxyTLSSuite) TestMutualTLS() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*2)
+	defer cancel()
+
+	// MilvusClient talks to proxy over tls
+	showResp, err := c.MilvusClient.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{})
+	s.NoError(merr.CheckRPCCall(showResp, err))
+
+	addr := fmt.Sprintf("localhost:%d", paramtable.Get().ProxyGrpcServerCfg.Port.GetAsInt())
+	dial := func(creds credentials.TransportCredentials) error {
+		dialCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		conn, err := grpc.DialContext(dialCtx, addr, grpc.WithBlock(), grpc.WithTransportCredentials(creds))
+		if err == nil {
+			conn.Close()
+		}
+		return err
+	}
+
+	// a client without the ca can't verify proxy
+	cert, err := tls.LoadX509KeyPair(path.Join(certDir, "client.pem"), path.Join(certDir, "client.key"))
+	s.Require().NoError(err)
+	s.Error(dial(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ServerName:   "localhost",
+		MinVersion:   tls.VersionTLS13,
+	})))
+
+	// neither can a client without tls
+	s.Error(dial(insecure.NewCredentials()))
+
+	log.Info("TestMutualTLS succeed")
+}
+
+func TestProxyTLS(t *testing.T) {
+	suite.Run(t, new(ProxyTLSSuite))
+}
@@ -0,0 +1,89 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynode
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type QueryNodeDistributionSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *QueryNodeDistributionSuite) TestGetDistribution() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim        = 128
+		dbName     = ""
+		rowNum     = 1000
+		channelNum = 2
+		segmentNum = 2
+	)
+	collectionName := "TestGetDistribution" + funcutil.GenRandomStr()
+
+	extra, err := c.AddQueryNode()
+	s.Require().NoError(err)
+	mainID, extraID := c.QueryNode.GetQueryNode().GetNodeID(), extra.GetQueryNode().GetNodeID()
+
+	_, err = c.CreateAndLoadCollection(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       channelNum,
+		SegmentNum:       segmentNum,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(err)
+
+	// every segment and channel is served by one of the querynodes
+	s.Eventually(func() bool {
+		distribution, err := c.GetQueryNodeDistribution(ctx)
+		if err != nil || len(distribution) != 2 {
+			return false
+		}
+		segments, channels := 0, 0
+		for _, dist := range distribution {
+			segments += len(dist.GetSegments())
+			channels += len(dist.GetChannels())
+		}
+		return segments == channelNum*segmentNum && channels == channelNum
+	}, time.Minute, time.Second)
+
+	// the stopped querynode is left out and reported
+	s.NoError(extra.Stop())
+	distribution, err := c.GetQueryNodeDistribution(ctx)
+	s.Error(err)
+	s.Contains(distribution, mainID)
+	s.NotContains(distribution, extraID)
+
+	log.Info("TestGetDistribution succeed")
+}
+
+func TestQueryNodeDistribution(t *testing.T) {
+	suite.Run(t, new(QueryNodeDistributionSuite))
+}
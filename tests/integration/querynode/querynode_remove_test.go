@@ -0,0 +1,105 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynode
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type QueryNodeRemoveSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *QueryNodeRemoveSuite) segmentNodes(ctx context.Context, collectionName string) []int64 {
+	resp, err := s.Cluster.Proxy.GetQuerySegmentInfo(ctx, &milvuspb.GetQuerySegmentInfoRequest{
+		CollectionName: collectionName,
+	})
+	s.Require().NoError(merr.CheckRPCCall(resp, err))
+	return lo.Uniq(lo.FlatMap(resp.GetInfos(), func(info *milvuspb.QuerySegmentInfo, _ int) []int64 {
+		return info.GetNodeIds()
+	}))
+}
+
+func (s *QueryNodeRemoveSuite) TestRemoveQueryNode() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+	)
+	collectionName := "TestRemoveQueryNode" + funcutil.GenRandomStr()
+
+	first, err := c.AddQueryNode()
+	s.Require().NoError(err)
+	second, err := c.AddQueryNode()
+	s.Require().NoError(err)
+	removedID := first.GetQueryNode().GetNodeID()
+	s.Require().NoError(c.WaitForQueryNodeNum(ctx, 3))
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       2,
+		SegmentNum:       6,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+	s.Eventually(func() bool {
+		return lo.Contains(s.segmentNodes(ctx, collectionName), removedID)
+	}, time.Minute, time.Second)
+
+	s.Require().NoError(c.RemoveQueryNode(first))
+	s.Len(c.GetAllQueryNodes(), 2)
+	s.Contains(c.GetAllQueryNodes(), second)
+	s.NotContains(c.GetAllQueryNodes(), first)
+	s.NoError(c.WaitForQueryNodeNum(ctx, 2))
+
+	// the segments on the removed querynode are rebalanced to the others
+	s.Eventually(func() bool {
+		return !lo.Contains(s.segmentNodes(ctx, collectionName), removedID)
+	}, time.Minute*2, time.Second)
+	s.Eventually(func() bool {
+		count, err := c.QueryCount(ctx, collectionName, "")
+		return err == nil && count == 6*rowNum
+	}, time.Minute, time.Second)
+
+	// neither an untracked querynode nor the main one can be removed
+	s.Error(c.RemoveQueryNode(first))
+	s.Error(c.RemoveQueryNode(c.QueryNode))
+
+	log.Info("TestRemoveQueryNode succeed")
+}
+
+func TestQueryNodeRemove(t *testing.T) {
+	suite.Run(t, new(QueryNodeRemoveSuite))
+}
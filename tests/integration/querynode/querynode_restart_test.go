@@ -0,0 +1,99 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynode
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	grpcquerynode "github.com/milvus-io/milvus/internal/distributed/querynode"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type QueryNodeRestartSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *QueryNodeRestartSuite) TestRestartWithSameNodeID() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+	)
+	collectionName := "TestRestartWithSameNodeID" + funcutil.GenRandomStr()
+
+	extra, err := c.AddQueryNode()
+	s.Require().NoError(err)
+	nodeID := extra.GetQueryNode().GetNodeID()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       2,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	s.Require().NoError(c.RestartQueryNode(extra))
+	restarted, ok := lo.Find(c.GetAllQueryNodes(), func(node *grpcquerynode.Server) bool {
+		return node != c.QueryNode && node.GetQueryNode().GetNodeID() == nodeID
+	})
+	s.Require().True(ok)
+	s.NotSame(extra, restarted)
+	states, err := restarted.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+	s.NoError(merr.CheckRPCCall(states, err))
+	s.Equal(commonpb.StateCode_Healthy, states.GetState().GetStateCode())
+
+	// the restarted querynode rejoins with the same session
+	sessions, err := c.MetaWatcher.ShowSessions()
+	s.NoError(err)
+	s.Len(lo.Filter(sessions, func(session *sessionutil.SessionRaw, _ int) bool {
+		return session.ServerName == typeutil.QueryNodeRole && session.ServerID == nodeID
+	}), 1)
+
+	s.Eventually(func() bool {
+		count, err := c.QueryCount(ctx, collectionName, "")
+		return err == nil && count == 2*rowNum
+	}, time.Minute*2, time.Second)
+
+	// the main querynode is not an extra one
+	s.Error(c.RestartQueryNode(c.QueryNode))
+
+	log.Info("TestRestartWithSameNodeID succeed")
+}
+
+func TestQueryNodeRestart(t *testing.T) {
+	suite.Run(t, new(QueryNodeRestartSuite))
+}
@@ -0,0 +1,85 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querynode
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type QueryNodeScaleSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *QueryNodeScaleSuite) TestScaleQueryNodes() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim        = 128
+		dbName     = ""
+		rowNum     = 1000
+		channelNum = 2
+		segmentNum = 4
+	)
+	collectionName := "TestScaleQueryNodes" + funcutil.GenRandomStr()
+
+	s.Require().NoError(c.ScaleQueryNodes(ctx, 3))
+	s.Len(c.GetAllQueryNodes(), 3)
+
+	_, err := c.CreateAndLoadCollection(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       channelNum,
+		SegmentNum:       segmentNum,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(err)
+
+	// everything served by the removed querynodes is moved onto the main one
+	mainQueryNode := c.QueryNode
+	s.Require().NoError(c.ScaleQueryNodes(ctx, 1))
+	s.Equal(mainQueryNode, c.QueryNode)
+	distribution, err := c.GetQueryNodeDistribution(ctx)
+	s.NoError(err)
+	s.Require().Len(distribution, 1)
+	dist := distribution[c.QueryNode.GetQueryNode().GetNodeID()]
+	s.NotEmpty(dist.GetSegments())
+	s.Len(dist.GetChannels(), channelNum)
+
+	count, err := c.QueryCount(ctx, collectionName, "")
+	s.NoError(err)
+	s.EqualValues(segmentNum*rowNum, count)
+
+	// the main querynode is never removed
+	s.Error(c.ScaleQueryNodes(ctx, 0))
+
+	log.Info("TestScaleQueryNodes succeed")
+}
+
+func TestQueryNodeScale(t *testing.T) {
+	suite.Run(t, new(QueryNodeScaleSuite))
+}
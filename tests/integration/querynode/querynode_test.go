@@ -216,7 +216,8 @@ func (s *QueryNodeSuite) insertBatchCollections(prefix string, collectionBatchSi
 
 func (s *QueryNodeSuite) setupData() {
 	// Add the second query node
-	s.Cluster.AddQueryNode()
+	_, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
 	goRoutineNum := s.maxGoRoutineNum
 	if goRoutineNum > s.numCollections {
 		goRoutineNum = s.numCollections
@@ -270,8 +271,10 @@ func (s *QueryNodeSuite) checkQNRestarts() {
 	// Stop all query nodes
 	s.Cluster.StopAllQueryNodes()
 	// Add new Query nodes.
-	s.Cluster.AddQueryNode()
-	s.Cluster.AddQueryNode()
+	_, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
+	_, err = s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
 
 	time.Sleep(s.waitTimeInSec)
 	for i := 0; i < 1000; i++ {
@@ -291,8 +294,10 @@ func (s *QueryNodeSuite) TestSwapQN() {
 	time.Sleep(s.waitTimeInSec)
 	s.checkAllCollectionsReady()
 	// Test case with new Query nodes added
-	s.Cluster.AddQueryNode()
-	s.Cluster.AddQueryNode()
+	_, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
+	_, err = s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
 	time.Sleep(s.waitTimeInSec)
 	s.checkAllCollectionsReady()
 
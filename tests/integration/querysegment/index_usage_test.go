@@ -0,0 +1,110 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querysegment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type IndexUsageSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *IndexUsageSuite) TestQuerySegmentIndexUsage() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 2000
+	)
+	collectionName := "TestQuerySegmentIndexUsage" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	// rows inserted after load stay in growing segments
+	insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldsData:     []*schemapb.FieldData{integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim)},
+		HashKeys:       integration.GenerateHashKeys(rowNum),
+		NumRows:        uint32(rowNum),
+	})
+	s.NoError(merr.CheckRPCCall(insertResult, err))
+
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+	segments, err := c.MetaWatcher.ShowSegments()
+	s.NoError(err)
+
+	usage, err := c.GetQuerySegmentIndexUsage(ctx, collectionName)
+	s.NoError(err)
+
+	// every reported segment is a sealed one with index built
+	s.NotEmpty(usage)
+	for segmentID, servedByIndex := range usage {
+		s.True(servedByIndex, "sealed segment %d shall be served from index", segmentID)
+	}
+
+	var growingNum int
+	for _, segment := range segments {
+		if segment.GetCollectionID() != describeResp.GetCollectionID() || segment.GetState() != commonpb.SegmentState_Growing {
+			continue
+		}
+		growingNum++
+		s.False(usage[segment.GetID()], "growing segment %d shall be served from raw data", segment.GetID())
+	}
+	s.NotZero(growingNum)
+
+	log.Info("TestQuerySegmentIndexUsage succeed")
+}
+
+func TestQuerySegmentIndexUsage(t *testing.T) {
+	suite.Run(t, new(IndexUsageSuite))
+}
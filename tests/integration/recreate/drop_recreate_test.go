@@ -0,0 +1,137 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recreate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const (
+	dim    = 128
+	dbName = ""
+	rowNum = 2000
+	topk   = 10
+)
+
+type DropRecreateSuite struct {
+	integration.MiniClusterSuite
+}
+
+// insertAndLoad inserts rowNum rows with primary keys starting from start, then indexes and loads the collection.
+func (s *DropRecreateSuite) insertAndLoad(ctx context.Context, collectionName string, start int64) []int64 {
+	c := s.Cluster
+	insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldsData: []*schemapb.FieldData{
+			integration.NewInt64FieldDataWithStart(integration.Int64Field, rowNum, start),
+			integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim),
+		},
+		HashKeys: integration.GenerateHashKeys(rowNum),
+		NumRows:  rowNum,
+	})
+	s.Require().NoError(merr.CheckRPCCall(insertResult, err))
+
+	flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+		DbName:          dbName,
+		CollectionNames: []string{collectionName},
+	})
+	s.Require().NoError(merr.CheckRPCCall(flushResp, err))
+	segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+	s.Require().True(has)
+	flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+	s.Require().True(has)
+	s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.Require().NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.Require().NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	return insertResult.GetIDs().GetIntId().GetData()
+}
+
+func (s *DropRecreateSuite) TestSearchAfterDropAndRecreate() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	collectionName := "TestSearchAfterDropAndRecreate" + funcutil.GenRandomStr()
+	schema := integration.ConstructSchema(collectionName, dim, false)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	oldPKs := s.insertAndLoad(ctx, collectionName, 0)
+	s.NoError(c.VerifyCollectionPKs(ctx, dbName, collectionName, integration.Int64Field, oldPKs))
+
+	_, err = c.DropAndRecreateCollection(ctx, dbName, schema, common.DefaultShardsNum)
+	s.Require().NoError(err)
+
+	// the recreated collection gets primary keys disjoint from the dropped one
+	newPKs := s.insertAndLoad(ctx, collectionName, rowNum)
+	s.NoError(c.VerifyCollectionPKs(ctx, dbName, collectionName, integration.Int64Field, newPKs))
+
+	params := integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2)
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, "", integration.FloatVecField,
+		schemapb.DataType_FloatVector, nil, metric.L2, params, 10, dim, topk, -1)
+	searchResult, err := c.Proxy.Search(ctx, searchReq)
+	s.NoError(merr.CheckRPCCall(searchResult, err))
+	ids := searchResult.GetResults().GetIds().GetIntId().GetData()
+	s.NotEmpty(ids)
+	s.Empty(lo.Intersect(ids, oldPKs), "search returns rows of the dropped collection")
+	s.Subset(newPKs, ids)
+
+	log.Info("TestSearchAfterDropAndRecreate succeed")
+}
+
+func TestDropRecreate(t *testing.T) {
+	suite.Run(t, new(DropRecreateSuite))
+}
@@ -0,0 +1,116 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reduceconcurrency
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type ReduceConcurrencySuite struct {
+	integration.MiniClusterSuite
+
+	readConcurrentRatio float64
+}
+
+func (s *ReduceConcurrencySuite) SetupTest() {
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithMaxReadConcurrentRatio(s.readConcurrentRatio))
+}
+
+func (s *ReduceConcurrencySuite) TestSearchManySegments() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 500
+		segNum = 10
+		nq     = 10
+		topk   = 20
+		rounds = 10
+	)
+	collectionName := "TestSearchManySegments" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       2,
+		SegmentNum:       segNum,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	params := integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2)
+	searchReq := integration.ConstructSearchRequestWithConsistencyLevel(dbName, collectionName, "",
+		integration.FloatVecField, schemapb.DataType_FloatVector, nil, metric.L2, params, nq, dim, topk, -1,
+		false, commonpb.ConsistencyLevel_Strong)
+
+	countBefore, latencyBefore := c.GetSearchReduceLatency()
+	var first []int64
+	for i := 0; i < rounds; i++ {
+		searchResult, err := c.Proxy.Search(ctx, searchReq)
+		s.Require().NoError(merr.CheckRPCCall(searchResult, err))
+		result := searchResult.GetResults()
+		s.Require().Equal(lo.RepeatBy(nq, func(int) int64 { return topk }), result.GetTopks())
+
+		// every query returns distinct hits ordered from the nearest one
+		for qi := 0; qi < nq; qi++ {
+			ids := result.GetIds().GetIntId().GetData()[qi*topk : (qi+1)*topk]
+			s.Len(lo.Uniq(ids), topk)
+			s.True(slices.IsSorted(result.GetScores()[qi*topk : (qi+1)*topk]))
+		}
+		// and the same search always returns the same hits
+		if first == nil {
+			first = result.GetIds().GetIntId().GetData()
+		} else {
+			s.Equal(first, result.GetIds().GetIntId().GetData())
+		}
+	}
+	countAfter, latencyAfter := c.GetSearchReduceLatency()
+	s.Greater(countAfter, countBefore)
+	log.Info("search results reduced", zap.Float64("readConcurrentRatio", s.readConcurrentRatio),
+		zap.Uint64("reduces", countAfter-countBefore), zap.Duration("latency", latencyAfter-latencyBefore))
+
+	log.Info("TestSearchManySegments succeed")
+}
+
+func TestLowReduceConcurrency(t *testing.T) {
+	// a tiny ratio is rounded up to a single read task, which reduces the results one search after another
+	suite.Run(t, &ReduceConcurrencySuite{readConcurrentRatio: 0.01})
+}
+
+func TestHighReduceConcurrency(t *testing.T) {
+	suite.Run(t, &ReduceConcurrencySuite{readConcurrentRatio: 4})
+}
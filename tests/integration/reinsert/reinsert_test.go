@@ -0,0 +1,149 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reinsert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type ReinsertSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *ReinsertSuite) TestDeleteThenReinsert() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		pk     = int64(1)
+	)
+	collectionName := "TestDeleteThenReinsert" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, false)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	flush := func() {
+		flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+			DbName:          dbName,
+			CollectionNames: []string{collectionName},
+		})
+		s.NoError(merr.CheckRPCCall(flushResp, err))
+		segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+		s.Require().True(has)
+		flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+		s.Require().True(has)
+		s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+	}
+
+	oldVecColumn := integration.NewFloatVectorFieldData(integration.FloatVecField, 1, dim)
+	insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldsData: []*schemapb.FieldData{
+			integration.NewInt64FieldDataWithStart(integration.Int64Field, 1, pk),
+			oldVecColumn,
+		},
+		HashKeys: integration.GenerateHashKeys(1),
+		NumRows:  1,
+	})
+	s.NoError(merr.CheckRPCCall(insertResult, err))
+	flush()
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+	s.NoError(c.CheckRowByPK(ctx, dbName, collectionName, integration.Int64Field, pk,
+		integration.FloatVecField, oldVecColumn.GetVectors().GetFloatVector().GetData()))
+
+	// delete and re-insert the same pk with another vector
+	newVecColumn := integration.NewFloatVectorFieldData(integration.FloatVecField, 1, dim)
+	err = c.DeleteAndReinsert(ctx, dbName, collectionName, integration.Int64Field, []int64{pk}, []*schemapb.FieldData{
+		integration.NewInt64FieldDataWithStart(integration.Int64Field, 1, pk),
+		newVecColumn,
+	})
+	s.NoError(err)
+	newVector := newVecColumn.GetVectors().GetFloatVector().GetData()
+	s.NoError(c.CheckRowByPK(ctx, dbName, collectionName, integration.Int64Field, pk, integration.FloatVecField, newVector))
+
+	flush()
+	s.NoError(c.CheckRowByPK(ctx, dbName, collectionName, integration.Int64Field, pk, integration.FloatVecField, newVector))
+
+	// compact
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+	compactResp, err := c.Proxy.ManualCompaction(ctx, &milvuspb.ManualCompactionRequest{
+		CollectionID: describeResp.GetCollectionID(),
+	})
+	s.NoError(merr.CheckRPCCall(compactResp, err))
+	s.Eventually(func() bool {
+		resp, err := c.Proxy.GetCompactionState(ctx, &milvuspb.GetCompactionStateRequest{
+			CompactionID: compactResp.GetCompactionID(),
+		})
+		if err != nil {
+			return false
+		}
+		return resp.GetState() == commonpb.CompactionState_Completed
+	}, 3*time.Minute, time.Second)
+	s.NoError(c.CheckRowByPK(ctx, dbName, collectionName, integration.Int64Field, pk, integration.FloatVecField, newVector))
+
+	log.Info("TestDeleteThenReinsert succeed")
+}
+
+func TestReinsert(t *testing.T) {
+	suite.Run(t, new(ReinsertSuite))
+}
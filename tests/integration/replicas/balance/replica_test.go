@@ -66,7 +66,8 @@ func (s *ReplicaTestSuit) initCollection(collectionName string, replica int, cha
 	})
 
 	for i := 1; i < replica; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	// load
@@ -0,0 +1,107 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consistency
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type ReplicaConsistencySuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *ReplicaConsistencySuite) SetupSuite() {
+	paramtable.Init()
+	paramtable.Get().Save(paramtable.Get().ProxyCfg.ReplicaSelectionPolicy.Key, "round_robin")
+
+	s.Require().NoError(s.SetupEmbedEtcd())
+}
+
+func (s *ReplicaConsistencySuite) TearDownSuite() {
+	paramtable.Get().Reset(paramtable.Get().ProxyCfg.ReplicaSelectionPolicy.Key)
+
+	s.MiniClusterSuite.TearDownSuite()
+}
+
+func (s *ReplicaConsistencySuite) TestIdenticalResultsAcrossReplicas() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim      = 128
+		dbName   = ""
+		replicas = 2
+		topk     = 10
+	)
+	collectionName := "TestIdenticalResultsAcrossReplicas" + funcutil.GenRandomStr()
+
+	// one querynode per replica
+	_, err := c.AddQueryNode()
+	s.Require().NoError(err)
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       2,
+		RowNumPerSegment: 2000,
+		Dim:              dim,
+		ReplicaNumber:    replicas,
+		// the replicas are searched with the params of the index, whatever it is
+		IndexType:  integration.IndexHNSW,
+		MetricType: metric.IP,
+	})
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		ReplicaNumber:  replicas,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	vector := make([]float32, dim)
+	for i := range vector {
+		vector[i] = rand.Float32()
+	}
+	results, err := c.SearchAllReplicas(ctx, collectionName, vector, topk)
+	s.Require().NoError(err)
+	s.Require().Len(results, replicas)
+	s.Len(results[0].GetResults().GetIds().GetIntId().GetData(), topk)
+	for _, result := range results[1:] {
+		s.NoError(integration.CompareSearchResults(results[0], result))
+	}
+
+	log.Info("TestIdenticalResultsAcrossReplicas succeed")
+}
+
+func TestReplicaConsistency(t *testing.T) {
+	suite.Run(t, new(ReplicaConsistencySuite))
+}
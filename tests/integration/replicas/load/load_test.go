@@ -60,10 +60,6 @@ func (s *LoadTestSuite) SetupSuite() {
 	// setup env value to init etcd source
 	s.T().Setenv("etcd.endpoints", val)
 
-	for key, value := range integration.DefaultParams() {
-		s.T().Setenv(key, value)
-	}
-
 	paramtable.Init()
 	paramtable.Get().Save(paramtable.Get().QueryCoordCfg.BalanceCheckInterval.Key, "1000")
 	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.GracefulStopTimeout.Key, "1")
@@ -137,7 +133,8 @@ func (s *LoadTestSuite) TestLoadWithPredefineCollectionLevelConfig() {
 	s.Len(resp.GetResourceGroups(), rgNum+1)
 
 	for i := 1; i < rgNum; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	s.Eventually(func() bool {
@@ -267,7 +264,8 @@ func (s *LoadTestSuite) TestLoadWithPredefineDatabaseLevelConfig() {
 	s.Len(resp.GetResourceGroups(), rgNum+1)
 
 	for i := 1; i < rgNum; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	s.Eventually(func() bool {
@@ -414,7 +412,8 @@ func (s *LoadTestSuite) TestLoadWithPredefineClusterLevelConfig() {
 	s.Len(resp.GetResourceGroups(), rgNum+1)
 
 	for i := 1; i < rgNum; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	s.Eventually(func() bool {
@@ -536,7 +535,8 @@ func (s *LoadTestSuite) TestDynamicUpdateLoadConfigs() {
 	s.Len(resp.GetResourceGroups(), rgNum+1)
 
 	for i := 1; i < rgNum; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	nodesInRG := make(map[string][]int64)
@@ -641,7 +641,8 @@ func (s *LoadTestSuite) TestDynamicUpdateLoadConfigs_WithoutRG() {
 
 	// prepare resource groups
 	for i := 1; i < 5; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	// load collection
@@ -818,7 +819,8 @@ func (s *LoadTestSuite) TestDynamicUpdateLoadConfigs_WithRGLackOfNode() {
 
 	// add qn back,  expect each replica has shard leaders
 	for i := 0; i < rgNum; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	s.Eventually(func() bool {
@@ -901,7 +903,8 @@ func (s *LoadTestSuite) TestDynamicUpdateLoadConfigs_OnLoadingCollection() {
 	s.Len(resp.GetResourceGroups(), rgNum+1)
 
 	for i := 1; i < rgNum; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	nodesInRG := make(map[string][]int64)
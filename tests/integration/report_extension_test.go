@@ -0,0 +1,92 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/internal/util/hookutil"
+)
+
+type ReportExtensionSuite struct {
+	suite.Suite
+}
+
+func opReport(opType string) map[string]any {
+	return map[string]any{hookutil.OpTypeKey: opType}
+}
+
+func (s *ReportExtensionSuite) TestSubscribe() {
+	e := NewReportChanExtension()
+	queries, cancelQueries := e.Subscribe(ReportOfOpType(hookutil.OpTypeQuery))
+	all, cancelAll := e.Subscribe(func(any) bool { return true })
+	defer cancelAll()
+
+	e.Report(opReport(hookutil.OpTypeInsert))
+	e.Report(opReport(hookutil.OpTypeQuery))
+	e.Report(opReport(hookutil.OpTypeSearch))
+
+	// only the reports of queries are received
+	s.Equal(opReport(hookutil.OpTypeQuery), <-queries)
+	s.Empty(queries)
+	s.Len(all, 3)
+
+	// the channel is closed on cancel, and nothing is received any more
+	cancelQueries()
+	cancelQueries()
+	e.Report(opReport(hookutil.OpTypeQuery))
+	_, ok := <-queries
+	s.False(ok)
+	s.Len(all, 4)
+}
+
+func (s *ReportExtensionSuite) TestSlowSubscriber() {
+	e := NewReportChanExtension()
+	slow, cancel := e.Subscribe(func(any) bool { return true })
+	defer cancel()
+
+	// reports are dropped instead of blocking once the buffer of the subscriber is full
+	for i := 0; i < 2*reportSubscriberBufferSize; i++ {
+		e.Report(opReport(hookutil.OpTypeInsert))
+	}
+	s.Len(slow, reportSubscriberBufferSize)
+}
+
+func (s *ReportExtensionSuite) TestBuffer() {
+	// reports are dropped unless someone is receiving
+	e := NewReportChanExtension()
+	e.Report(opReport(hookutil.OpTypeInsert))
+	s.EqualValues(1, e.Drained())
+
+	// or kept until the buffer is full
+	e = NewReportChanExtensionWithBuffer(2)
+	for i := 0; i < 3; i++ {
+		e.Report(opReport(hookutil.OpTypeInsert))
+	}
+	s.Len(e.GetReportChan(), 2)
+	s.EqualValues(1, e.Drained())
+	<-e.GetReportChan()
+	e.Report(opReport(hookutil.OpTypeInsert))
+	s.Len(e.GetReportChan(), 2)
+	s.EqualValues(1, e.Drained())
+}
+
+func TestReportExtension(t *testing.T) {
+	suite.Run(t, new(ReportExtensionSuite))
+}
@@ -87,12 +87,14 @@ func (s *ResourceGroupTestSuite) TestResourceGroup() {
 	s.assertResourceGroup(ctx)
 
 	s.rgs[DefaultResourceGroup].expectedNodeNum = 2
-	s.Cluster.AddQueryNode()
+	_, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
 	s.syncResourceConfig(ctx)
 	s.assertResourceGroup(ctx)
 
 	s.rgs[RecycleResourceGroup].expectedNodeNum = 3
-	s.Cluster.AddQueryNodes(3)
+	_, err = s.Cluster.AddQueryNodes(3)
+	s.Require().NoError(err)
 	s.syncResourceConfig(ctx)
 	s.assertResourceGroup(ctx)
 
@@ -166,7 +168,8 @@ func (s *ResourceGroupTestSuite) TestWithReplica() {
 
 	// create resource group
 	s.initResourceGroup(ctx)
-	s.Cluster.AddQueryNodes(3)
+	_, err := s.Cluster.AddQueryNodes(3)
+	s.Require().NoError(err)
 	time.Sleep(100 * time.Millisecond)
 	s.assertResourceGroup(ctx)
 
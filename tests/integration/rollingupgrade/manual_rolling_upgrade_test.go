@@ -154,7 +154,8 @@ func (s *ManualRollingUpgradeSuite) TestTransfer() {
 	qn1 := qnServer1.GetQueryNode()
 
 	// add new querynode
-	qnSever2 := s.Cluster.AddQueryNode()
+	qnSever2, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
 	time.Sleep(5 * time.Second)
 	qn2 := qnSever2.GetQueryNode()
 
@@ -300,7 +301,8 @@ func (s *ManualRollingUpgradeSuite) TestSuspendNode() {
 	log.Info("Create index done")
 
 	// add new querynode
-	qnSever2 := s.Cluster.AddQueryNode()
+	qnSever2, err := s.Cluster.AddQueryNode()
+	s.Require().NoError(err)
 	time.Sleep(5 * time.Second)
 	qn2 := qnSever2.GetQueryNode()
 
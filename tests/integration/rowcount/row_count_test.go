@@ -0,0 +1,136 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rowcount
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type RowCountSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *RowCountSuite) TestRowCountAfterDelete() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim       = 128
+		dbName    = ""
+		rowNum    = 1000
+		deleteNum = 100
+	)
+	collectionName := "TestRowCountAfterDelete" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, false)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	flush := func() {
+		flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+			DbName:          dbName,
+			CollectionNames: []string{collectionName},
+		})
+		s.NoError(merr.CheckRPCCall(flushResp, err))
+		segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+		s.Require().True(has)
+		flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+		s.Require().True(has)
+		s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+	}
+
+	insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldsData: []*schemapb.FieldData{
+			integration.NewInt64FieldData(integration.Int64Field, rowNum),
+			integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim),
+		},
+		HashKeys: integration.GenerateHashKeys(rowNum),
+		NumRows:  uint32(rowNum),
+	})
+	s.NoError(merr.CheckRPCCall(insertResult, err))
+	flush()
+
+	rowCount, err := c.GetRowCount(ctx, dbName, collectionName)
+	s.NoError(err)
+	s.Equal(int64(rowNum), rowCount)
+
+	pks := lo.Map(lo.Range(deleteNum), func(pk int, _ int) string { return fmt.Sprint(pk) })
+	deleteResult, err := c.Proxy.Delete(ctx, &milvuspb.DeleteRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Expr:           fmt.Sprintf("%s in [%s]", integration.Int64Field, strings.Join(pks, ",")),
+	})
+	s.NoError(merr.CheckRPCCall(deleteResult, err))
+	s.Equal(int64(deleteNum), deleteResult.GetDeleteCnt())
+	flush()
+
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+
+	// the deletes are only reflected once compaction rewrites the segment,
+	// keep triggering compaction since the mix compaction waits for the l0 one.
+	s.Eventually(func() bool {
+		rowCount, err := c.GetRowCount(ctx, dbName, collectionName)
+		if err == nil && rowCount == rowNum-deleteNum {
+			return true
+		}
+		log.Info("row count not reconciled yet", zap.Int64("rowCount", rowCount), zap.Error(err))
+		compactResp, err := c.Proxy.ManualCompaction(ctx, &milvuspb.ManualCompactionRequest{
+			CollectionID: describeResp.GetCollectionID(),
+		})
+		if err := merr.CheckRPCCall(compactResp, err); err != nil {
+			log.Warn("fail to trigger compaction", zap.Error(err))
+		}
+		return false
+	}, 3*time.Minute, 5*time.Second)
+
+	log.Info("TestRowCountAfterDelete succeed")
+}
+
+func TestRowCount(t *testing.T) {
+	suite.Run(t, new(RowCountSuite))
+}
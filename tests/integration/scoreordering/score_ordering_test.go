@@ -0,0 +1,83 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scoreordering
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type ScoreOrderingSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *ScoreOrderingSuite) run(metricType string) {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 2000
+		nq     = 3
+		topk   = 20
+	)
+	collectionName := "TestScoreOrdering" + metricType + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       2,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+		MetricType:       metricType,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, "", integration.FloatVecField,
+		schemapb.DataType_FloatVector, nil, metricType, integration.GetSearchParams(integration.IndexFaissIvfFlat, metricType), nq, dim, topk, -1)
+	searchResult, err := c.Proxy.Search(ctx, searchReq)
+	s.Require().NoError(merr.CheckRPCCall(searchResult, err))
+	s.Require().Len(searchResult.GetResults().GetTopks(), nq)
+	s.NoError(integration.CheckScoreOrdering(searchResult.GetResults(), metricType))
+}
+
+func (s *ScoreOrderingSuite) TestL2() {
+	s.run(metric.L2)
+	log.Info("TestL2 succeed")
+}
+
+func (s *ScoreOrderingSuite) TestCosine() {
+	s.run(metric.COSINE)
+	log.Info("TestCosine succeed")
+}
+
+func TestScoreOrdering(t *testing.T) {
+	suite.Run(t, new(ScoreOrderingSuite))
+}
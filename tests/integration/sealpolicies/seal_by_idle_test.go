@@ -0,0 +1,106 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sealpolicies
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const idleSealTimeout = 5 * time.Second
+
+type SealByIdleSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *SealByIdleSuite) SetupTest() {
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithSegmentIdleSeal(idleSealTimeout))
+}
+
+func (s *SealByIdleSuite) TestSealIdleSegment() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*3)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 100
+	)
+	collectionName := "TestSealIdleSegment" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+
+	// a few rows, far below any size threshold, and no flush
+	insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldsData:     []*schemapb.FieldData{integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim)},
+		HashKeys:       integration.GenerateHashKeys(rowNum),
+		NumRows:        rowNum,
+	})
+	s.NoError(merr.CheckRPCCall(insertResult, err))
+
+	// the segment stays growing before the idle timeout
+	time.Sleep(idleSealTimeout / 2)
+	segments, err := c.MetaWatcher.ShowSegments()
+	s.NoError(err)
+	for _, segment := range segments {
+		if segment.GetCollectionID() == describeResp.GetCollectionID() {
+			s.Equal(commonpb.SegmentState_Growing, segment.GetState())
+		}
+	}
+
+	// and gets sealed once idle for long enough
+	segments, err = c.WaitForSegmentsSealed(ctx, describeResp.GetCollectionID())
+	s.NoError(err)
+	s.NotEmpty(segments)
+
+	log.Info("TestSealIdleSegment succeed")
+}
+
+func TestSealByIdle(t *testing.T) {
+	suite.Run(t, new(SealByIdleSuite))
+}
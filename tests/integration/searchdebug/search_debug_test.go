@@ -0,0 +1,188 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searchdebug
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type SearchDebugSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *SearchDebugSuite) TestSegmentAttribution() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim     = 128
+		dbName  = ""
+		nq      = 10
+		topk    = 50
+		segNum  = 3
+		rowsNum = 2000
+	)
+	collectionName := "TestSegmentAttribution" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       segNum,
+		RowNumPerSegment: rowsNum,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	params := integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2)
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, "", integration.FloatVecField,
+		schemapb.DataType_FloatVector, nil, metric.L2, params, nq, dim, topk, -1)
+
+	searchResult, attribution, err := c.SearchWithDebug(ctx, searchReq)
+	s.Require().NoError(err)
+	ids := searchResult.GetResults().GetIds().GetIntId().GetData()
+	s.Require().Len(ids, nq*topk)
+
+	// every hit comes from a flushed segment of unique primary keys, so each of them shall be attributed to one segment
+	for _, id := range ids {
+		s.Len(attribution[id], 1, "id %d attributed to %v", id, attribution[id])
+	}
+	contributors := lo.Uniq(lo.Flatten(lo.Values(attribution)))
+	log.Info("search segment attribution", zap.Int64s("contributors", contributors))
+	s.Greater(len(contributors), 1)
+
+	log.Info("TestSegmentAttribution succeed")
+}
+
+func (s *SearchDebugSuite) TestGrowingAndDuplicateAttribution() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		nq     = 10
+		topk   = 50
+		rowNum = 1000
+	)
+	collectionName := "TestGrowingAndDuplicateAttribution" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, false)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.Require().NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:           dbName,
+		CollectionName:   collectionName,
+		Schema:           marshaledSchema,
+		ShardsNum:        1,
+		ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+	})
+	s.Require().NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	insert := func(start int64) {
+		insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			FieldsData: []*schemapb.FieldData{
+				integration.NewInt64FieldDataWithStart(integration.Int64Field, rowNum, start),
+				integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim),
+			},
+			HashKeys: integration.GenerateHashKeys(rowNum),
+			NumRows:  uint32(rowNum),
+		})
+		s.Require().NoError(merr.CheckRPCCall(insertResult, err))
+	}
+
+	// the same primary keys are flushed into two sealed segments
+	for i := 0; i < 2; i++ {
+		insert(0)
+		_, err := c.FlushAndWait(ctx, collectionName)
+		s.Require().NoError(err)
+	}
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.Require().NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.Require().NoError(c.WaitForIndexBuilt(ctx, dbName, collectionName, integration.FloatVecField))
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.Require().NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	// the primary keys after rowNum are left in growing segments
+	insert(rowNum)
+
+	params := integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2)
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, "", integration.FloatVecField,
+		schemapb.DataType_FloatVector, nil, metric.L2, params, nq, dim, topk, -1)
+
+	searchResult, attribution, err := c.SearchWithDebug(ctx, searchReq)
+	s.Require().NoError(err)
+	ids := searchResult.GetResults().GetIds().GetIntId().GetData()
+	s.Require().Len(ids, nq*topk)
+
+	growing := 0
+	for _, id := range ids {
+		if id >= rowNum {
+			growing++
+			s.Empty(attribution[id], "growing id %d attributed to %v", id, attribution[id])
+			continue
+		}
+		// a duplicated primary key is attributed to both sealed segments holding it
+		s.Len(attribution[id], 2, "duplicated id %d attributed to %v", id, attribution[id])
+	}
+	s.Greater(growing, 0)
+	s.Less(growing, len(ids))
+
+	log.Info("TestGrowingAndDuplicateAttribution succeed")
+}
+
+func TestSearchDebug(t *testing.T) {
+	suite.Run(t, new(SearchDebugSuite))
+}
@@ -0,0 +1,81 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searchfailure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type SearchFailureSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *SearchFailureSuite) TestQueryNodeSearchFailure() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 2000
+		nq     = 2
+		topk   = 10
+	)
+	collectionName := "TestQueryNodeSearchFailure" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	// inject before the collection is loaded, so that proxy searches through the faulty querynode
+	injected := merr.WrapErrServiceInternal("index of segment corrupted")
+	c.InjectSearchFailure(c.QueryNode, injected)
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	params := integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2)
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, "", integration.FloatVecField,
+		schemapb.DataType_FloatVector, nil, metric.L2, params, nq, dim, topk, -1)
+	s.NoError(c.CheckSearchFailure(ctx, searchReq, injected, 30*time.Second))
+
+	// search is served again once querynode recovers
+	c.InjectSearchFailure(c.QueryNode, nil)
+	searchResult, err := c.Proxy.Search(ctx, searchReq)
+	s.Require().NoError(merr.CheckRPCCall(searchResult, err))
+	s.Len(searchResult.GetResults().GetScores(), nq*topk)
+
+	log.Info("TestQueryNodeSearchFailure succeed")
+}
+
+func TestSearchFailure(t *testing.T) {
+	suite.Run(t, new(SearchFailureSuite))
+}
@@ -0,0 +1,108 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shardleader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type ShardLeaderFailoverSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *ShardLeaderFailoverSuite) SetupSuite() {
+	paramtable.Init()
+	paramtable.Get().Save(paramtable.Get().QueryCoordCfg.BalanceCheckInterval.Key, "1000")
+	paramtable.Get().Save(paramtable.Get().QueryNodeCfg.GracefulStopTimeout.Key, "1")
+
+	s.Require().NoError(s.SetupEmbedEtcd())
+}
+
+func (s *ShardLeaderFailoverSuite) TestShardLeaderFailover() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+	)
+	collectionName := "TestShardLeaderFailover" + funcutil.GenRandomStr()
+
+	// a standby querynode to take over the shard
+	_, err := c.AddQueryNode()
+	s.Require().NoError(err)
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       2,
+		RowNumPerSegment: 2000,
+		Dim:              dim,
+		ReplicaNumber:    1,
+	})
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+
+	oldLeader, newLeader, err := c.ShardLeaderFailover(ctx, describeResp.GetCollectionID())
+	s.NoError(err)
+	s.NotEqual(oldLeader, newLeader)
+	// the stopped shard leader is no longer tracked by the cluster
+	for _, node := range c.GetAllQueryNodes() {
+		s.NotEqual(oldLeader, node.GetQueryNode().GetNodeID())
+	}
+
+	// search shall be served by the new shard leader
+	params := integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2)
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, "", integration.FloatVecField,
+		schemapb.DataType_FloatVector, nil, metric.L2, params, 10, dim, 10, -1)
+	s.Eventually(func() bool {
+		searchResult, err := c.Proxy.Search(ctx, searchReq)
+		return merr.CheckRPCCall(searchResult, err) == nil
+	}, time.Minute, time.Second)
+
+	log.Info("TestShardLeaderFailover succeed")
+}
+
+func TestShardLeaderFailover(t *testing.T) {
+	suite.Run(t, new(ShardLeaderFailoverSuite))
+}
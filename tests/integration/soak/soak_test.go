@@ -0,0 +1,91 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package soak
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type SoakSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *SoakSuite) TestInsertSearchSoak() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 100
+		topk   = 10
+	)
+	collectionName := "TestInsertSearchSoak" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       1,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, "", integration.FloatVecField,
+		schemapb.DataType_FloatVector, nil, metric.L2, integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2), 1, dim, topk, -1)
+	iterations := 0
+	err := c.Soak(ctx, 30*time.Second, func(cluster *integration.MiniClusterV2) error {
+		insertResult, err := cluster.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			FieldsData:     []*schemapb.FieldData{integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim)},
+			HashKeys:       integration.GenerateHashKeys(rowNum),
+			NumRows:        rowNum,
+		})
+		if err := merr.CheckRPCCall(insertResult, err); err != nil {
+			return err
+		}
+		searchResult, err := cluster.Proxy.Search(ctx, searchReq)
+		if err := merr.CheckRPCCall(searchResult, err); err != nil {
+			return err
+		}
+		iterations++
+		return nil
+	})
+	s.NoError(err)
+	s.Positive(iterations)
+
+	log.Info("TestInsertSearchSoak succeed")
+}
+
+func TestSoak(t *testing.T) {
+	suite.Run(t, new(SoakSuite))
+}
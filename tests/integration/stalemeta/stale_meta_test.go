@@ -0,0 +1,105 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stalemeta
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/metastore/kv/rootcoord"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
+	"github.com/milvus-io/milvus/pkg/v2/util"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const staleCollectionID = 100
+
+type StaleMetaSuite struct {
+	integration.MiniClusterSuite
+
+	staleCollectionName string
+}
+
+func (s *StaleMetaSuite) SetupTest() {
+	paramtable.Init()
+	s.staleCollectionName = "TestStaleMeta" + funcutil.GenRandomStr()
+
+	// a collection left in creating state, as if rootcoord crashed during CreateCollection.
+	staleInfo := &etcdpb.CollectionInfo{
+		ID:     staleCollectionID,
+		DbId:   util.DefaultDBID,
+		Schema: &schemapb.CollectionSchema{Name: s.staleCollectionName},
+		State:  etcdpb.CollectionState_CollectionCreating,
+	}
+	bs, err := proto.Marshal(staleInfo)
+	s.Require().NoError(err)
+	key := path.Join(paramtable.Get().EtcdCfg.MetaSubPath.GetValue(), rootcoord.BuildCollectionKey(util.DefaultDBID, staleCollectionID))
+
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithPreSeededEtcd(map[string][]byte{key: bs}))
+}
+
+func (s *StaleMetaSuite) TestStartWithStaleCollectionMeta() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*3)
+	defer cancel()
+
+	// the stale collection must never become visible.
+	hasResp, err := c.Proxy.HasCollection(ctx, &milvuspb.HasCollectionRequest{
+		CollectionName: s.staleCollectionName,
+	})
+	s.NoError(merr.CheckRPCCall(hasResp, err))
+	s.False(hasResp.GetValue())
+
+	showResp, err := c.Proxy.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{})
+	s.NoError(merr.CheckRPCCall(showResp, err))
+	s.False(lo.Contains(showResp.GetCollectionIds(), staleCollectionID))
+
+	// the name of the stale collection shall be reusable once it's cleaned.
+	schema := integration.ConstructSchema(s.staleCollectionName, 128, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	s.Eventually(func() bool {
+		status, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+			CollectionName: s.staleCollectionName,
+			Schema:         marshaledSchema,
+			ShardsNum:      common.DefaultShardsNum,
+		})
+		return merr.CheckRPCCall(status, err) == nil
+	}, time.Minute, time.Second)
+
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: s.staleCollectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+	s.NotEqual(int64(staleCollectionID), describeResp.GetCollectionID())
+}
+
+func TestStaleMeta(t *testing.T) {
+	suite.Run(t, new(StaleMetaSuite))
+}
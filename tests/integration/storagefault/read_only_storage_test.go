@@ -0,0 +1,122 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagefault
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type ReadOnlyStorageSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *ReadOnlyStorageSuite) TestFlushOnReadOnlyStorage() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*3)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 3000
+	)
+	collectionName := "TestFlushOnReadOnlyStorage" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+	describeResp, err := c.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(describeResp, err))
+
+	insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		FieldsData:     []*schemapb.FieldData{integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim)},
+		HashKeys:       integration.GenerateHashKeys(rowNum),
+		NumRows:        rowNum,
+	})
+	s.NoError(merr.CheckRPCCall(insertResult, err))
+
+	c.SetStorageReadOnly(true)
+	flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+		DbName:          dbName,
+		CollectionNames: []string{collectionName},
+	})
+	s.NoError(merr.CheckRPCCall(flushResp, err))
+
+	// the sync keeps failing on the read-only storage, stay within its retry budget
+	flushCtx, flushCancel := context.WithTimeout(ctx, 3*time.Second)
+	err = c.WaitForAllFlushed(flushCtx, []string{collectionName})
+	flushCancel()
+	s.Error(err)
+	s.Greater(c.RejectedStorageWrites(), int64(0))
+
+	// no segment is flushed, and nothing missing in storage is recorded in meta
+	segments, err := c.MetaWatcher.ShowSegments()
+	s.NoError(err)
+	for _, segment := range segments {
+		if segment.GetCollectionID() != describeResp.GetCollectionID() {
+			continue
+		}
+		s.NotEqual(commonpb.SegmentState_Flushed, segment.GetState())
+		s.NoError(c.CheckSegmentBinlogsExist(ctx, segment))
+	}
+
+	// the pending flush completes once the storage is writable again
+	c.SetStorageReadOnly(false)
+	s.NoError(c.WaitForAllFlushed(ctx, []string{collectionName}))
+	segments, err = c.MetaWatcher.ShowSegments()
+	s.NoError(err)
+	for _, segment := range segments {
+		if segment.GetCollectionID() == describeResp.GetCollectionID() {
+			s.NoError(c.CheckSegmentBinlogsExist(ctx, segment))
+		}
+	}
+	rowCount, err := c.GetRowCount(ctx, dbName, collectionName)
+	s.NoError(err)
+	s.Equal(int64(rowNum), rowCount)
+
+	log.Info("TestFlushOnReadOnlyStorage succeed")
+}
+
+func TestReadOnlyStorage(t *testing.T) {
+	suite.Run(t, new(ReadOnlyStorageSuite))
+}
@@ -0,0 +1,99 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagepath
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type StoragePathSuite struct {
+	integration.MiniClusterSuite
+
+	storagePath string
+}
+
+func (s *StoragePathSuite) SetupTest() {
+	s.storagePath = path.Join(s.T().TempDir(), "storage")
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithStoragePath(s.storagePath))
+}
+
+func (s *StoragePathSuite) TestUniquePaths() {
+	rootPath := paramtable.Get().EtcdCfg.RootPath.GetValue()
+
+	// every cluster gets its own paths
+	first, second := integration.DefaultParams(), integration.DefaultParams()
+	for _, key := range []string{"etcd.rootPath", "msgChannel.chanNamePrefix.cluster", "minio.rootPath", "localStorage.path"} {
+		s.NotEqual(first[key], second[key], key)
+	}
+	s.NotEqual(rootPath, first["etcd.rootPath"])
+	s.NotEqual(rootPath, second["etcd.rootPath"])
+
+	log.Info("TestUniquePaths succeed")
+}
+
+func (s *StoragePathSuite) TestPinnedStoragePath() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+	)
+	collectionName := "TestPinnedStoragePath" + funcutil.GenRandomStr()
+
+	s.Equal(s.storagePath, paramtable.Get().LocalStorageCfg.Path.GetValue())
+	s.Equal(s.storagePath, c.ChunkManager.RootPath())
+
+	// the binlogs are flushed under the pinned path
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       1,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	files := 0
+	err := filepath.WalkDir(s.storagePath, func(_ string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files++
+		}
+		return err
+	})
+	s.NoError(err)
+	s.Positive(files)
+
+	log.Info("TestPinnedStoragePath succeed")
+}
+
+func TestStoragePath(t *testing.T) {
+	suite.Run(t, new(StoragePathSuite))
+}
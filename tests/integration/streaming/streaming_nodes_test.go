@@ -0,0 +1,90 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streaming
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/util/streamingutil"
+	"github.com/milvus-io/milvus/pkg/v2/proto/streamingpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type StreamingNodesSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *StreamingNodesSuite) SetupSuite() {
+	streamingutil.SetStreamingServiceEnabled()
+	s.MiniClusterSuite.SetupSuite()
+}
+
+func (s *StreamingNodesSuite) TearDownSuite() {
+	s.MiniClusterSuite.TearDownSuite()
+	streamingutil.UnsetStreamingServiceEnabled()
+}
+
+func (s *StreamingNodesSuite) SetupTest() {
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithStreamingNodes(2))
+}
+
+func (s *StreamingNodesSuite) TestMultipleStreamingNodes() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*3)
+	defer cancel()
+	c := s.Cluster
+
+	nodes := c.GetAllStreamingNodes()
+	s.Len(nodes, 2)
+	for _, node := range nodes {
+		s.Equal(commonpb.StateCode_Healthy, node.Health(ctx))
+	}
+
+	sessions, err := c.MetaWatcher.ShowSessions()
+	s.NoError(err)
+	nodeIDs := typeutil.NewUniqueSet()
+	for _, session := range sessions {
+		if session.ServerName == typeutil.StreamingNodeRole {
+			nodeIDs.Insert(session.ServerID)
+		}
+	}
+	s.Equal(2, nodeIDs.Len())
+
+	// the pchannels should be balanced onto both streaming nodes.
+	s.Eventually(func() bool {
+		pchannels, err := c.MetaWatcher.ShowPChannels()
+		if err != nil {
+			return false
+		}
+		servingNodes := typeutil.NewUniqueSet()
+		for _, pchannel := range pchannels {
+			if pchannel.GetState() == streamingpb.PChannelMetaState_PCHANNEL_META_STATE_ASSIGNED {
+				servingNodes.Insert(pchannel.GetNode().GetServerId())
+			}
+		}
+		return servingNodes.Len() == 2 && nodeIDs.Contain(servingNodes.Collect()...)
+	}, time.Minute, time.Second)
+}
+
+func TestStreamingNodes(t *testing.T) {
+	suite.Run(t, new(StreamingNodesSuite))
+}
@@ -0,0 +1,113 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streaming
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/util/streamingutil"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type StreamingRestartSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *StreamingRestartSuite) SetupSuite() {
+	streamingutil.SetStreamingServiceEnabled()
+	s.MiniClusterSuite.SetupSuite()
+}
+
+func (s *StreamingRestartSuite) TearDownSuite() {
+	s.MiniClusterSuite.TearDownSuite()
+	streamingutil.UnsetStreamingServiceEnabled()
+}
+
+func (s *StreamingRestartSuite) TestRecoverGrowingSegments() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+	)
+	collectionName := "TestRecoverGrowingSegments" + funcutil.GenRandomStr()
+
+	collectionID, err := c.CreateAndLoadCollection(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       1,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(err)
+
+	// the rows inserted now are only in growing segments of the wal
+	_, err = c.InsertAutoID(ctx, dbName, collectionName, rowNum, dim)
+	s.Require().NoError(err)
+	segments, err := c.MetaWatcher.ListSegments(collectionID)
+	s.NoError(err)
+	s.NotEmpty(lo.Filter(segments, func(segment *datapb.SegmentInfo, _ int) bool {
+		return segment.GetState() == commonpb.SegmentState_Growing
+	}))
+
+	s.Require().NoError(c.RestartStreamingNode())
+	s.Equal(commonpb.StateCode_Healthy, c.StreamingNode.Health(ctx))
+
+	// the growing rows are recovered from the wal
+	s.Eventually(func() bool {
+		count, err := c.QueryCount(ctx, collectionName, "")
+		return err == nil && count == 2*rowNum
+	}, time.Minute*2, time.Second)
+
+	// and persisted once flushed
+	flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+		DbName:          dbName,
+		CollectionNames: []string{collectionName},
+	})
+	s.Require().NoError(merr.CheckRPCCall(flushResp, err))
+	s.WaitForFlush(ctx, flushResp.GetCollSegIDs()[collectionName].GetData(), flushResp.GetCollFlushTs()[collectionName], dbName, collectionName)
+	segments, err = c.MetaWatcher.ListSegments(collectionID)
+	s.NoError(err)
+	flushedRows := lo.SumBy(segments, func(segment *datapb.SegmentInfo) int64 {
+		if segment.GetState() == commonpb.SegmentState_Flushed {
+			return segment.GetNumOfRows()
+		}
+		return 0
+	})
+	s.EqualValues(2*rowNum, flushedRows)
+
+	log.Info("TestRecoverGrowingSegments succeed")
+}
+
+func TestStreamingRestart(t *testing.T) {
+	suite.Run(t, new(StreamingRestartSuite))
+}
@@ -0,0 +1,95 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streaming
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/util/streamingutil"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/message"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type WALAppendSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *WALAppendSuite) SetupSuite() {
+	streamingutil.SetStreamingServiceEnabled()
+	s.MiniClusterSuite.SetupSuite()
+}
+
+func (s *WALAppendSuite) TearDownSuite() {
+	s.MiniClusterSuite.TearDownSuite()
+	streamingutil.UnsetStreamingServiceEnabled()
+}
+
+func (s *WALAppendSuite) TestInsertAppendsCoalesced() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*3)
+	defer cancel()
+	c := s.Cluster
+
+	const (
+		dim          = 128
+		dbName       = ""
+		batchNum     = 5
+		rowsPerBatch = 1000
+	)
+	collectionName := "TestInsertAppendsCoalesced_" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	before := c.GetWALAppendCount(message.MessageTypeInsert)
+	for i := 0; i < batchNum; i++ {
+		insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			FieldsData:     []*schemapb.FieldData{integration.NewFloatVectorFieldData(integration.FloatVecField, rowsPerBatch, dim)},
+			HashKeys:       integration.GenerateHashKeys(rowsPerBatch),
+			NumRows:        uint32(rowsPerBatch),
+		})
+		s.NoError(merr.CheckRPCCall(insertResult, err))
+	}
+	appended := c.GetWALAppendCount(message.MessageTypeInsert) - before
+
+	// rows of an insert request are appended to the wal in a few messages rather than one message per row
+	s.Greater(appended, int64(0))
+	s.Less(appended, int64(batchNum*rowsPerBatch))
+}
+
+func TestWALAppend(t *testing.T) {
+	suite.Run(t, new(WALAppendSuite))
+}
@@ -0,0 +1,97 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamingtoggle
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/util/streamingutil"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type StreamingToggleSuite struct {
+	integration.MiniClusterSuite
+
+	enabled      bool
+	env          string
+	localEnabled bool
+}
+
+func (s *StreamingToggleSuite) SetupTest() {
+	s.env = os.Getenv(streamingutil.MilvusStreamingServiceEnabled)
+	s.localEnabled = paramtable.IsLocalComponentEnabled(typeutil.StreamingNodeRole)
+	s.MiniClusterSuite.SetupTestWithOptions(integration.WithStreamingService(s.enabled))
+}
+
+// TearDownTest checks the process-wide switches of the streaming service are restored once the cluster stops.
+func (s *StreamingToggleSuite) TearDownTest() {
+	s.MiniClusterSuite.TearDownTest()
+	s.Equal(s.env, os.Getenv(streamingutil.MilvusStreamingServiceEnabled))
+	s.Equal(s.localEnabled, paramtable.IsLocalComponentEnabled(typeutil.StreamingNodeRole))
+}
+
+func (s *StreamingToggleSuite) TestLoadCollection() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+	)
+	collectionName := "TestLoadCollection" + funcutil.GenRandomStr()
+
+	// the streaming node is brought up only with the streaming service
+	s.Equal(s.enabled, streamingutil.IsStreamingServiceEnabled())
+	s.Equal(s.enabled, c.StreamingNode != nil)
+
+	_, err := c.CreateAndLoadCollection(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       2,
+		SegmentNum:       2,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(err)
+
+	count, err := c.QueryCount(ctx, collectionName, "")
+	s.NoError(err)
+	s.EqualValues(2*rowNum, count)
+
+	log.Info("TestLoadCollection succeed", zap.Bool("streaming", s.enabled))
+}
+
+func TestStreamingToggle(t *testing.T) {
+	t.Run("streaming", func(t *testing.T) {
+		suite.Run(t, &StreamingToggleSuite{enabled: true})
+	})
+	t.Run("non-streaming", func(t *testing.T) {
+		suite.Run(t, &StreamingToggleSuite{enabled: false})
+	})
+}
@@ -100,21 +100,30 @@ func (s *MiniClusterSuite) TearDownSuite() {
 }
 
 func (s *MiniClusterSuite) SetupTest() {
+	s.SetupTestWithOptions()
+}
+
+// SetupTestWithOptions starts the mini cluster with extra options,
+// suites which need a customized cluster could call it in their own SetupTest.
+func (s *MiniClusterSuite) SetupTestWithOptions(opts ...OptionV2) {
 	log.SetLevel(zapcore.InfoLevel)
 	s.T().Log("Setup test...")
-	// setup mini cluster to use embed etcd
-	endpoints := etcd.GetEmbedEtcdEndpoints(s.EtcdServer)
-	val := strings.Join(endpoints, ",")
-	// setup env value to init etcd source
-	s.T().Setenv("etcd.endpoints", val)
+	// setup mini cluster to use embed etcd, unless the suite skips it for WithEmbeddedEtcd
+	if s.EtcdServer != nil {
+		endpoints := etcd.GetEmbedEtcdEndpoints(s.EtcdServer)
+		val := strings.Join(endpoints, ",")
+		// setup env value to init etcd source
+		s.T().Setenv("etcd.endpoints", val)
+		opts = append([]OptionV2{func(c *MiniClusterV2) {
+			// change config etcd endpoints
+			c.params[params.EtcdCfg.Endpoints.Key] = val
+		}}, opts...)
+	}
 
 	s.T().Log("Setup case timeout", caseTimeout)
 	ctx, cancel := context.WithTimeout(context.Background(), caseTimeout)
 	s.cancelFunc = cancel
-	c, err := StartMiniClusterV2(ctx, func(c *MiniClusterV2) {
-		// change config etcd endpoints
-		c.params[params.EtcdCfg.Endpoints.Key] = val
-	})
+	c, err := StartMiniClusterV2(ctx, opts...)
 	s.Require().NoError(err)
 	s.Cluster = c
 
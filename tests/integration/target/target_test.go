@@ -96,7 +96,8 @@ func (s *TargetTestSuit) initCollection(collectionName string, replica int, chan
 	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
 
 	for i := 1; i < replica; i++ {
-		s.Cluster.AddQueryNode()
+		_, err := s.Cluster.AddQueryNode()
+		s.Require().NoError(err)
 	}
 
 	// load
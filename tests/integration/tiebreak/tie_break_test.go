@@ -0,0 +1,154 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tiebreak
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type TieBreakSuite struct {
+	integration.MiniClusterSuite
+}
+
+// newSameFloatVectorFieldData returns numRows identical vectors, so that every row has the same distance to any query.
+func newSameFloatVectorFieldData(fieldName string, numRows, dim int) *schemapb.FieldData {
+	data := make([]float32, numRows*dim)
+	for i := range data {
+		data[i] = float32(i%dim) / float32(dim)
+	}
+	return &schemapb.FieldData{
+		Type:      schemapb.DataType_FloatVector,
+		FieldName: fieldName,
+		Field: &schemapb.FieldData_Vectors{
+			Vectors: &schemapb.VectorField{
+				Dim: int64(dim),
+				Data: &schemapb.VectorField_FloatVector{
+					FloatVector: &schemapb.FloatArray{Data: data},
+				},
+			},
+		},
+	}
+}
+
+func (s *TieBreakSuite) TestSearchTieBreakByPK() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 1000
+		nq     = 2
+		topk   = 20
+		rounds = 5
+	)
+	collectionName := "TestSearchTieBreakByPK" + funcutil.GenRandomStr()
+
+	schema := integration.ConstructSchema(collectionName, dim, false)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	// the segment flushed first holds the larger pks, so the tie can't be broken by segment order
+	for _, start := range []int64{rowNum, 0} {
+		insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+			DbName:         dbName,
+			CollectionName: collectionName,
+			FieldsData: []*schemapb.FieldData{
+				integration.NewInt64FieldDataWithStart(integration.Int64Field, rowNum, start),
+				newSameFloatVectorFieldData(integration.FloatVecField, rowNum, dim),
+			},
+			HashKeys: integration.GenerateHashKeys(rowNum),
+			NumRows:  uint32(rowNum),
+		})
+		s.NoError(merr.CheckRPCCall(insertResult, err))
+
+		flushResp, err := c.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+			DbName:          dbName,
+			CollectionNames: []string{collectionName},
+		})
+		s.NoError(merr.CheckRPCCall(flushResp, err))
+		segmentIDs, has := flushResp.GetCollSegIDs()[collectionName]
+		s.Require().True(has)
+		flushTs, has := flushResp.GetCollFlushTs()[collectionName]
+		s.Require().True(has)
+		s.WaitForFlush(ctx, segmentIDs.GetData(), flushTs, dbName, collectionName)
+	}
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIDMap, metric.L2),
+	})
+	s.NoError(merr.CheckRPCCall(createIndexStatus, err))
+	s.WaitForIndexBuilt(ctx, collectionName, integration.FloatVecField)
+
+	loadStatus, err := c.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+	})
+	s.NoError(merr.CheckRPCCall(loadStatus, err))
+	s.WaitForLoad(ctx, collectionName)
+
+	params := integration.GetSearchParams(integration.IndexFaissIDMap, metric.L2)
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, "", integration.FloatVecField,
+		schemapb.DataType_FloatVector, nil, metric.L2, params, nq, dim, topk, -1)
+
+	var firstIDs []int64
+	for i := 0; i < rounds; i++ {
+		searchResult, err := c.Proxy.Search(ctx, searchReq)
+		s.NoError(merr.CheckRPCCall(searchResult, err))
+		results := searchResult.GetResults()
+		s.NoError(integration.CheckSearchTieBreak(results))
+
+		ids := results.GetIds().GetIntId().GetData()
+		s.Len(ids, nq*topk)
+		if firstIDs == nil {
+			firstIDs = ids
+			continue
+		}
+		s.Equal(firstIDs, ids)
+	}
+
+	log.Info("TestSearchTieBreakByPK succeed")
+}
+
+func TestTieBreak(t *testing.T) {
+	suite.Run(t, new(TieBreakSuite))
+}
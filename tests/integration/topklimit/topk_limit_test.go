@@ -0,0 +1,86 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topklimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const topKLimit = 100
+
+type TopKLimitSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *TopKLimitSuite) SetupSuite() {
+	paramtable.Init()
+	paramtable.Get().Save(paramtable.Get().QuotaConfig.TopKLimit.Key, strconv.Itoa(topKLimit))
+
+	s.Require().NoError(s.SetupEmbedEtcd())
+}
+
+func (s *TopKLimitSuite) TearDownSuite() {
+	paramtable.Get().Reset(paramtable.Get().QuotaConfig.TopKLimit.Key)
+
+	s.MiniClusterSuite.TearDownSuite()
+}
+
+func (s *TopKLimitSuite) TestOversizedTopK() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim    = 128
+		dbName = ""
+		rowNum = 2000
+	)
+	collectionName := "TestOversizedTopK" + funcutil.GenRandomStr()
+
+	s.CreateCollectionWithConfiguration(ctx, &integration.CreateCollectionConfig{
+		DBName:           dbName,
+		CollectionName:   collectionName,
+		ChannelNum:       1,
+		SegmentNum:       1,
+		RowNumPerSegment: rowNum,
+		Dim:              dim,
+	})
+	s.Require().NoError(c.LoadCollectionFields(ctx, dbName, collectionName, nil))
+
+	// a search at the limit is fine, one beyond it shall be rejected
+	searchReq := integration.ConstructSearchRequest(dbName, collectionName, "", integration.FloatVecField,
+		schemapb.DataType_FloatVector, nil, metric.L2, integration.GetSearchParams(integration.IndexFaissIvfFlat, metric.L2), 1, dim, topKLimit, -1)
+	s.NoError(c.CheckOversizedTopK(ctx, searchReq))
+
+	log.Info("TestOversizedTopK succeed")
+}
+
+func TestTopKLimit(t *testing.T) {
+	suite.Run(t, new(TopKLimitSuite))
+}
@@ -0,0 +1,77 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topology
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+type TopologySuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *TopologySuite) TestDescribeTopology() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	extra, err := c.AddQueryNode()
+	s.Require().NoError(err)
+	s.Require().NoError(c.WaitForQueryNodeNum(ctx, 2))
+
+	topology, err := c.DescribeTopology(ctx)
+	s.Require().NoError(err)
+	log.Info("cluster topology\n" + topology.String())
+	for _, role := range []string{typeutil.RootCoordRole, typeutil.DataCoordRole, typeutil.QueryCoordRole, typeutil.ProxyRole, typeutil.DataNodeRole} {
+		s.Len(topology.ByRole(role), 1, role)
+	}
+	querynodes := topology.ByRole(typeutil.QueryNodeRole)
+	s.Require().Len(querynodes, 2)
+	s.Equal(c.QueryNode.GetQueryNode().GetNodeID(), querynodes[0].NodeID)
+	s.Equal(extra.GetQueryNode().GetNodeID(), querynodes[1].NodeID)
+	for _, node := range topology.Nodes {
+		s.False(node.Missing, node.Name)
+		s.Equal(commonpb.StateCode_Healthy, node.State, node.Name)
+		s.NotEmpty(node.Address, node.Name)
+	}
+
+	// stopped nodes are reported as they are
+	s.NoError(extra.Stop())
+	c.StopProxy()
+	topology, err = c.DescribeTopology(ctx)
+	s.Require().NoError(err)
+	log.Info("cluster topology\n" + topology.String())
+	s.NotEqual(commonpb.StateCode_Healthy, topology.ByRole(typeutil.QueryNodeRole)[1].State)
+	s.True(topology.ByRole(typeutil.ProxyRole)[0].Missing)
+	s.Contains(topology.String(), "Missing")
+	c.StartProxy()
+
+	log.Info("TestDescribeTopology succeed")
+}
+
+func TestTopology(t *testing.T) {
+	suite.Run(t, new(TopologySuite))
+}
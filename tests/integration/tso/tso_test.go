@@ -0,0 +1,147 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tso
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/tsoutil"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+const dim = 128
+
+type TSOSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *TSOSuite) TestAllocTimestampBatches() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute)
+	defer cancel()
+
+	const batchSize = 4
+	tss, err := c.AllocTimestampBatches(ctx, 100, batchSize)
+	s.NoError(err)
+	gaps, err := integration.TimestampGaps(tss)
+	s.NoError(err)
+	// batches never overlap
+	for _, gap := range gaps {
+		s.GreaterOrEqual(gap, uint64(batchSize))
+	}
+}
+
+func (s *TSOSuite) TestInsertBurstTimestamps() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*3)
+	defer cancel()
+
+	collectionName := "TestInsertBurstTimestamps" + funcutil.GenRandomStr()
+	schema := integration.ConstructSchema(collectionName, dim, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      common.DefaultShardsNum,
+	})
+	s.NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	insert := func() uint64 {
+		const rowNum = 10
+		insertResult, err := c.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+			CollectionName: collectionName,
+			FieldsData:     []*schemapb.FieldData{integration.NewFloatVectorFieldData(integration.FloatVecField, rowNum, dim)},
+			HashKeys:       integration.GenerateHashKeys(rowNum),
+			NumRows:        rowNum,
+		})
+		s.NoError(merr.CheckRPCCall(insertResult, err))
+		return insertResult.GetTimestamp()
+	}
+
+	// sequential inserts get increasing timestamps
+	sequential := make([]uint64, 0, 20)
+	for i := 0; i < 20; i++ {
+		sequential = append(sequential, insert())
+	}
+	_, err = integration.TimestampGaps(sequential)
+	s.NoError(err)
+
+	// a burst of concurrent inserts gets unique timestamps
+	const burst = 50
+	var mu sync.Mutex
+	concurrent := make([]uint64, 0, burst)
+	wg := sync.WaitGroup{}
+	for i := 0; i < burst; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ts := insert()
+			mu.Lock()
+			concurrent = append(concurrent, ts)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	s.Len(lo.Uniq(concurrent), burst)
+	sort.Slice(concurrent, func(i, j int) bool { return concurrent[i] < concurrent[j] })
+	_, err = integration.TimestampGaps(concurrent)
+	s.NoError(err)
+	s.Greater(concurrent[0], sequential[len(sequential)-1])
+}
+
+func (s *TSOSuite) TestBackwardClockJump() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute)
+	defer cancel()
+
+	const batchSize = 4
+	before, err := c.AllocTimestampBatches(ctx, 20, batchSize)
+	s.NoError(err)
+
+	c.ApplyClockJump(-time.Hour)
+	defer c.ApplyClockJump(time.Hour)
+	// let the timestamp oracle observe the jump
+	time.Sleep(time.Second)
+
+	after, err := c.AllocTimestampBatches(ctx, 20, batchSize)
+	s.NoError(err)
+
+	// the backward jump is not followed, timestamps keep increasing across it
+	_, err = integration.TimestampGaps(append(before, after...))
+	s.NoError(err)
+	beforePhysical, _ := tsoutil.ParseTS(before[len(before)-1])
+	afterPhysical, _ := tsoutil.ParseTS(after[0])
+	s.False(afterPhysical.Before(beforePhysical))
+}
+
+func TestTSO(t *testing.T) {
+	suite.Run(t, new(TSOSuite))
+}
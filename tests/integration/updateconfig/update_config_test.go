@@ -0,0 +1,125 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package updateconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/tests/integration"
+)
+
+// UpdateConfigSuite triggers mix compaction every second,
+// so that the small segments are compacted soon once auto compaction is enabled.
+type UpdateConfigSuite struct {
+	integration.MiniClusterSuite
+}
+
+func (s *UpdateConfigSuite) SetupSuite() {
+	s.MiniClusterSuite.SetupSuite()
+
+	paramtable.Init()
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.MixCompactionTriggerInterval.Key, "1")
+}
+
+func (s *UpdateConfigSuite) TearDownSuite() {
+	s.MiniClusterSuite.TearDownSuite()
+
+	paramtable.Get().Reset(paramtable.Get().DataCoordCfg.MixCompactionTriggerInterval.Key)
+}
+
+func (s *UpdateConfigSuite) TestDisableAutoCompaction() {
+	c := s.Cluster
+	ctx, cancel := context.WithTimeout(c.GetContext(), time.Minute*5)
+	defer cancel()
+
+	const (
+		dim        = 128
+		dbName     = ""
+		rowNum     = 1000
+		segmentNum = 4
+	)
+	collectionName := "TestDisableAutoCompaction" + funcutil.GenRandomStr()
+	key := paramtable.Get().DataCoordCfg.EnableAutoCompaction.Key
+
+	s.Require().NoError(c.UpdateConfig(key, "false"))
+	defer c.UpdateConfig(key, "true")
+	s.False(paramtable.Get().DataCoordCfg.EnableAutoCompaction.GetAsBool())
+
+	schema := integration.ConstructSchema(collectionName, dim, true)
+	marshaledSchema, err := proto.Marshal(schema)
+	s.Require().NoError(err)
+	createCollectionStatus, err := c.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      1,
+	})
+	s.Require().NoError(merr.CheckRPCCall(createCollectionStatus, err))
+
+	createIndexStatus, err := c.Proxy.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		CollectionName: collectionName,
+		FieldName:      integration.FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    integration.ConstructIndexParam(dim, integration.IndexFaissIvfFlat, metric.L2),
+	})
+	s.Require().NoError(merr.CheckRPCCall(createIndexStatus, err))
+
+	// small segments, which are mergeable once auto compaction is enabled
+	for i := 0; i < segmentNum; i++ {
+		_, err := c.InsertRandomData(ctx, collectionName, rowNum, int64(i))
+		s.Require().NoError(err)
+		_, err = c.FlushAndWait(ctx, collectionName)
+		s.Require().NoError(err)
+	}
+	s.Require().NoError(c.WaitForIndexBuilt(ctx, dbName, collectionName, integration.FloatVecField))
+
+	// no compaction is scheduled while auto compaction is disabled
+	s.Never(func() bool {
+		tasks, err := c.ListCompactionTasks(ctx, collectionName)
+		s.NoError(err)
+		return len(tasks) > 0
+	}, 10*time.Second, time.Second)
+
+	// and the small segments are compacted soon after it's enabled again
+	s.Require().NoError(c.UpdateConfig(key, "true"))
+	s.Eventually(func() bool {
+		tasks, err := c.ListCompactionTasks(ctx, collectionName)
+		return err == nil && len(tasks) > 0
+	}, time.Minute, time.Second)
+
+	// configs forbidden to be updated at runtime are rejected
+	prefix := paramtable.Get().CommonCfg.ClusterPrefix.GetValue()
+	s.Error(c.UpdateConfig(paramtable.Get().CommonCfg.ClusterPrefix.Key, "new-cluster"))
+	s.Equal(prefix, paramtable.Get().CommonCfg.ClusterPrefix.GetValue())
+
+	log.Info("TestDisableAutoCompaction succeed")
+}
+
+func TestUpdateConfig(t *testing.T) {
+	suite.Run(t, new(UpdateConfigSuite))
+}
@@ -0,0 +1,65 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/util"
+	"github.com/milvus-io/milvus/pkg/v2/util/crypto"
+)
+
+// defaultRootPassword is the default of common.security.defaultRootPassword.
+const defaultRootPassword = "Milvus"
+
+// rootCredential attaches the credential of root to every call, in the same form as the sdks do.
+type rootCredential struct {
+	token string
+}
+
+func newRootCredential(password string) *rootCredential {
+	return &rootCredential{token: crypto.Base64Encode(util.UserRoot + util.CredentialSeperator + password)}
+}
+
+func (c *rootCredential) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{strings.ToLower(util.HeaderAuthorize): c.token}, nil
+}
+
+func (c *rootCredential) RequireTransportSecurity() bool {
+	return false
+}
+
+// GetUnauthenticatedClient dials the proxy without any credential, so that rejections of unauthenticated calls
+// can be tested once WithAuthEnabled. The connection is closed on Stop.
+func (cluster *MiniClusterV2) GetUnauthenticatedClient() (milvuspb.MilvusServiceClient, error) {
+	if cluster.clientConn == nil {
+		return nil, errors.New("mini cluster not started, no proxy to dial")
+	}
+	addr := fmt.Sprintf("localhost:%d", params.ProxyGrpcServerCfg.Port.GetAsInt())
+	conn, err := grpc.DialContext(cluster.ctx, addr, cluster.getUnauthenticatedGrpcDialOpt()...)
+	if err != nil {
+		return nil, err
+	}
+	cluster.unauthConns = append(cluster.unauthConns, conn)
+	return milvuspb.NewMilvusServiceClient(conn), nil
+}
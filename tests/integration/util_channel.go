@@ -0,0 +1,153 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus/internal/datacoord"
+	kvdatacoord "github.com/milvus-io/milvus/internal/metastore/kv/datacoord"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/tsoutil"
+)
+
+// unassignedNodeID is the node id datacoord keeps the channels which are not watched by any datanode with.
+const unassignedNodeID = math.MinInt64
+
+// KeepUnassigned can be returned by the assign func of WithChannelAssignment to leave the channel unassigned for now,
+// datacoord tries to assign it again once it advances the channel states next time.
+const KeepUnassigned int64 = unassignedNodeID
+
+// pinnedChannelPolicyFactory assigns channels by the assign func and never balances them.
+type pinnedChannelPolicyFactory struct {
+	assign func(channel string, candidates []int64) int64
+}
+
+func (f *pinnedChannelPolicyFactory) NewBalancePolicy() datacoord.BalanceChannelPolicy {
+	return datacoord.EmptyBalancePolicy
+}
+
+func (f *pinnedChannelPolicyFactory) NewAssignPolicy() datacoord.AssignPolicy {
+	return func(currentCluster datacoord.Assignments, toAssign *datacoord.NodeChannelInfo, exclusiveNodes []int64) *datacoord.ChannelOpSet {
+		if toAssign == nil || len(toAssign.Channels) == 0 {
+			return nil
+		}
+		candidates := make([]int64, 0, len(currentCluster))
+		for _, info := range currentCluster {
+			if info.NodeID == toAssign.NodeID || lo.Contains(exclusiveNodes, info.NodeID) {
+				continue
+			}
+			candidates = append(candidates, info.NodeID)
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		assigned := make(map[int64][]datacoord.RWChannel)
+		moved := make([]datacoord.RWChannel, 0, len(toAssign.Channels))
+		for name, ch := range toAssign.Channels {
+			nodeID := f.assign(name, candidates)
+			if nodeID == KeepUnassigned {
+				continue
+			}
+			if !lo.Contains(candidates, nodeID) {
+				nodeID = candidates[0]
+			}
+			assigned[nodeID] = append(assigned[nodeID], ch)
+			moved = append(moved, ch)
+		}
+		if len(moved) == 0 {
+			return nil
+		}
+
+		ops := datacoord.NewChannelOpSet()
+		for nodeID, chs := range assigned {
+			ops.Append(nodeID, datacoord.Watch, chs...)
+		}
+		ops.Delete(toAssign.NodeID, moved...)
+		return ops
+	}
+}
+
+// GetChannelAssignment returns the datanode id which each channel is assigned to, keyed by channel name.
+// Channels not assigned to any datanode yet are left out.
+func (cluster *MiniClusterV2) GetChannelAssignment(ctx context.Context) (map[string]int64, error) {
+	prefix := path.Join(params.EtcdCfg.MetaRootPath.GetValue(), params.CommonCfg.DataCoordWatchSubPath.GetValue()) + "/"
+	resp, err := cluster.EtcdCli.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+	assignment := make(map[string]int64, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		// key is in the form of prefix/{nodeID}/{channel}
+		nodeKey, channel, ok := strings.Cut(strings.TrimPrefix(string(kv.Key), prefix), "/")
+		if !ok {
+			continue
+		}
+		nodeID, err := strconv.ParseInt(nodeKey, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if nodeID == unassignedNodeID {
+			continue
+		}
+		assignment[channel] = nodeID
+	}
+	return assignment, nil
+}
+
+// GetChannelCheckpointLag returns how far the checkpoint of each channel of the collection in the default database
+// falls behind now, keyed by channel name. The checkpoints are read from the meta of datacoord,
+// which datanodes update every dataNode.channel.updateChannelCheckpointInterval while the channel is idle.
+func (cluster *MiniClusterV2) GetChannelCheckpointLag(ctx context.Context, collection string) (map[string]time.Duration, error) {
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	lags := make(map[string]time.Duration, len(describeResp.GetVirtualChannelNames()))
+	for _, channel := range describeResp.GetVirtualChannelNames() {
+		key := path.Join(params.EtcdCfg.MetaRootPath.GetValue(), kvdatacoord.ChannelCheckpointPrefix, channel)
+		resp, err := cluster.EtcdCli.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Kvs) == 0 {
+			return nil, errors.Newf("channel %s of collection %s has no checkpoint", channel, collection)
+		}
+		position := &msgpb.MsgPosition{}
+		if err := proto.Unmarshal(resp.Kvs[0].Value, position); err != nil {
+			return nil, err
+		}
+		lags[channel] = max(now.Sub(tsoutil.PhysicalTime(position.GetTimestamp())), 0)
+	}
+	return lags, nil
+}
@@ -0,0 +1,101 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/metastore/kv/datacoord"
+	"github.com/milvus-io/milvus/internal/metastore/kv/rootcoord"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// CollectionMetaPrefixes returns the meta prefixes whose keys live and die with collections,
+// relative to the meta root path. Snapshots of rootcoord are not included since they are kept after drop.
+func CollectionMetaPrefixes() []string {
+	return []string{
+		rootcoord.CollectionInfoMetaPrefix,
+		rootcoord.PartitionMetaPrefix,
+		rootcoord.FieldMetaPrefix,
+		datacoord.SegmentPrefix,
+		datacoord.ChannelCheckpointPrefix,
+		params.CommonCfg.DataCoordWatchSubPath.GetValue(),
+	}
+}
+
+// CountMetaKeys returns the key count under each of the prefixes, which are relative to the meta root path.
+func (cluster *MiniClusterV2) CountMetaKeys(ctx context.Context, prefixes ...string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(prefixes))
+	for _, prefix := range prefixes {
+		key := path.Join(params.EtcdCfg.MetaRootPath.GetValue(), prefix) + "/"
+		resp, err := cluster.EtcdCli.Get(ctx, key, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		if err != nil {
+			return nil, err
+		}
+		counts[prefix] = resp.Count
+	}
+	return counts, nil
+}
+
+// ChurnCollections creates n collections one after another, inserts rowNum rows into each of them
+// when rowNum is positive, and drops them right after.
+func (cluster *MiniClusterV2) ChurnCollections(ctx context.Context, n, rowNum, dim int) error {
+	for i := 0; i < n; i++ {
+		collectionName := fmt.Sprintf("churn_%d_%s", i, funcutil.GenRandomStr())
+		marshaledSchema, err := proto.Marshal(ConstructSchema(collectionName, dim, true))
+		if err != nil {
+			return err
+		}
+		status, err := cluster.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+			CollectionName: collectionName,
+			Schema:         marshaledSchema,
+			ShardsNum:      common.DefaultShardsNum,
+		})
+		if err := merr.CheckRPCCall(status, err); err != nil {
+			return err
+		}
+
+		if rowNum > 0 {
+			insertResult, err := cluster.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+				CollectionName: collectionName,
+				FieldsData:     []*schemapb.FieldData{NewFloatVectorFieldData(FloatVecField, rowNum, dim)},
+				HashKeys:       GenerateHashKeys(rowNum),
+				NumRows:        uint32(rowNum),
+			})
+			if err := merr.CheckRPCCall(insertResult, err); err != nil {
+				return err
+			}
+		}
+
+		status, err = cluster.Proxy.DropCollection(ctx, &milvuspb.DropCollectionRequest{
+			CollectionName: collectionName,
+		})
+		if err := merr.CheckRPCCall(status, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
 
@@ -17,6 +20,16 @@ import (
 	"github.com/milvus-io/milvus/pkg/v2/util/metric"
 )
 
+const rowCountKey = "row_count"
+
+// ErrCollectionConflict is returned by CreateCollectionIdempotent when the collection
+// already exists with parameters different from the requested ones.
+var ErrCollectionConflict = errors.New("collection already exists with different parameters")
+
+// duplicateCollectionReason is the reason rootcoord rejects re-creating a collection with different parameters by,
+// it carries no error code of its own.
+const duplicateCollectionReason = "create duplicate collection with different parameters"
+
 type CreateCollectionConfig struct {
 	DBName           string
 	CollectionName   string
@@ -26,6 +39,33 @@ type CreateCollectionConfig struct {
 	Dim              int
 	ReplicaNumber    int32
 	ResourceGroups   []string
+	// IndexType and MetricType of the index on the vector field, IVF_FLAT and L2 if left empty
+	IndexType  string
+	MetricType string
+}
+
+func (cfg *CreateCollectionConfig) indexParams() []*commonpb.KeyValuePair {
+	indexType, metricType := cfg.IndexType, cfg.MetricType
+	if indexType == "" {
+		indexType = IndexFaissIvfFlat
+	}
+	if metricType == "" {
+		metricType = metric.L2
+	}
+	return ConstructIndexParam(cfg.Dim, indexType, metricType)
+}
+
+func (cfg *CreateCollectionConfig) properties() []*commonpb.KeyValuePair {
+	return []*commonpb.KeyValuePair{
+		{
+			Key:   common.CollectionReplicaNumber,
+			Value: strconv.FormatInt(int64(cfg.ReplicaNumber), 10),
+		},
+		{
+			Key:   common.CollectionResourceGroups,
+			Value: strings.Join(cfg.ResourceGroups, ","),
+		},
+	}
 }
 
 func (s *MiniClusterSuite) InsertAndFlush(ctx context.Context, dbName, collectionName string, rowNum, dim int) error {
@@ -38,12 +78,10 @@ func (s *MiniClusterSuite) InsertAndFlush(ctx context.Context, dbName, collectio
 		HashKeys:       hashKeys,
 		NumRows:        uint32(rowNum),
 	})
-	if err != nil {
+	// surface the error as is, e.g. ErrCollectionNotFound when inserting into a nonexistent collection
+	if err := merr.CheckRPCCall(insertResult, err); err != nil {
 		return err
 	}
-	if !merr.Ok(insertResult.Status) {
-		return merr.Error(insertResult.Status)
-	}
 
 	flushResp, err := s.Cluster.Proxy.Flush(ctx, &milvuspb.FlushRequest{
 		DbName:          dbName,
@@ -82,16 +120,7 @@ func (s *MiniClusterSuite) CreateCollectionWithConfiguration(ctx context.Context
 		CollectionName: cfg.CollectionName,
 		Schema:         marshaledSchema,
 		ShardsNum:      int32(cfg.ChannelNum),
-		Properties: []*commonpb.KeyValuePair{
-			{
-				Key:   common.CollectionReplicaNumber,
-				Value: strconv.FormatInt(int64(cfg.ReplicaNumber), 10),
-			},
-			{
-				Key:   common.CollectionResourceGroups,
-				Value: strings.Join(cfg.ResourceGroups, ","),
-			},
-		},
+		Properties:     cfg.properties(),
 	})
 	s.NoError(err)
 	s.True(merr.Ok(createCollectionStatus))
@@ -113,9 +142,287 @@ func (s *MiniClusterSuite) CreateCollectionWithConfiguration(ctx context.Context
 		CollectionName: cfg.CollectionName,
 		FieldName:      FloatVecField,
 		IndexName:      "_default",
-		ExtraParams:    ConstructIndexParam(cfg.Dim, IndexFaissIvfFlat, metric.L2),
+		ExtraParams:    cfg.indexParams(),
 	})
 	s.NoError(err)
 	s.True(merr.Ok(createIndexStatus))
 	s.WaitForIndexBuiltWithDB(ctx, cfg.DBName, cfg.CollectionName, FloatVecField)
 }
+
+// CreateCollectionIdempotent creates the collection of the schema, re-creating an existing collection
+// with an identical schema succeeds and reports existed as true,
+// while re-creating it with a conflicting schema fails with ErrCollectionConflict.
+// Other errors are returned as they are.
+func (cluster *MiniClusterV2) CreateCollectionIdempotent(ctx context.Context, dbName string, schema *schemapb.CollectionSchema, shardsNum int32) (bool, error) {
+	hasResp, err := cluster.Proxy.HasCollection(ctx, &milvuspb.HasCollectionRequest{
+		DbName:         dbName,
+		CollectionName: schema.GetName(),
+	})
+	if err := merr.CheckRPCCall(hasResp, err); err != nil {
+		return false, err
+	}
+	existed := hasResp.GetValue()
+
+	marshaledSchema, err := proto.Marshal(schema)
+	if err != nil {
+		return existed, err
+	}
+	status, err := cluster.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: schema.GetName(),
+		Schema:         marshaledSchema,
+		ShardsNum:      shardsNum,
+	})
+	if err := merr.CheckRPCCall(status, err); err != nil {
+		if existed && strings.Contains(err.Error(), duplicateCollectionReason) {
+			return existed, errors.Wrapf(ErrCollectionConflict, "collection: %s, reason: %s", schema.GetName(), err.Error())
+		}
+		return existed, err
+	}
+	return existed, nil
+}
+
+// CreateAutoIDCollection creates the collection of the default schema whose int64 primary keys are generated on insert.
+func (cluster *MiniClusterV2) CreateAutoIDCollection(ctx context.Context, dbName, collection string, dim int, shardsNum int32) error {
+	marshaledSchema, err := proto.Marshal(ConstructSchema(collection, dim, true))
+	if err != nil {
+		return err
+	}
+	status, err := cluster.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+		Schema:         marshaledSchema,
+		ShardsNum:      shardsNum,
+	})
+	return merr.CheckRPCCall(status, err)
+}
+
+// CreateAndLoadCollection drives MilvusClient to create the collection of the default schema,
+// insert and flush cfg.SegmentNum segments of cfg.RowNumPerSegment rows, build the index on the vector field
+// and load the collection, it blocks until the collection is fully loaded and returns the collection id.
+func (cluster *MiniClusterV2) CreateAndLoadCollection(ctx context.Context, cfg *CreateCollectionConfig) (int64, error) {
+	marshaledSchema, err := proto.Marshal(ConstructSchema(cfg.CollectionName, cfg.Dim, true))
+	if err != nil {
+		return 0, err
+	}
+	status, err := cluster.MilvusClient.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         cfg.DBName,
+		CollectionName: cfg.CollectionName,
+		Schema:         marshaledSchema,
+		ShardsNum:      int32(cfg.ChannelNum),
+		Properties:     cfg.properties(),
+	})
+	if err := merr.CheckRPCCall(status, err); err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < cfg.SegmentNum; i++ {
+		insertResult, err := cluster.MilvusClient.Insert(ctx, &milvuspb.InsertRequest{
+			DbName:         cfg.DBName,
+			CollectionName: cfg.CollectionName,
+			FieldsData:     []*schemapb.FieldData{NewFloatVectorFieldData(FloatVecField, cfg.RowNumPerSegment, cfg.Dim)},
+			HashKeys:       GenerateHashKeys(cfg.RowNumPerSegment),
+			NumRows:        uint32(cfg.RowNumPerSegment),
+		})
+		if err := merr.CheckRPCCall(insertResult, err); err != nil {
+			return 0, err
+		}
+		flushResp, err := cluster.MilvusClient.Flush(ctx, &milvuspb.FlushRequest{
+			DbName:          cfg.DBName,
+			CollectionNames: []string{cfg.CollectionName},
+		})
+		if err := merr.CheckRPCCall(flushResp, err); err != nil {
+			return 0, err
+		}
+	}
+
+	status, err = cluster.MilvusClient.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+		DbName:         cfg.DBName,
+		CollectionName: cfg.CollectionName,
+		FieldName:      FloatVecField,
+		IndexName:      "_default",
+		ExtraParams:    cfg.indexParams(),
+	})
+	if err := merr.CheckRPCCall(status, err); err != nil {
+		return 0, err
+	}
+	if err := cluster.WaitForIndexBuilt(ctx, cfg.DBName, cfg.CollectionName, FloatVecField); err != nil {
+		return 0, err
+	}
+
+	status, err = cluster.MilvusClient.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         cfg.DBName,
+		CollectionName: cfg.CollectionName,
+		ReplicaNumber:  cfg.ReplicaNumber,
+		ResourceGroups: cfg.ResourceGroups,
+	})
+	if err := merr.CheckRPCCall(status, err); err != nil {
+		return 0, err
+	}
+	for {
+		loadState, err := cluster.MilvusClient.GetLoadState(ctx, &milvuspb.GetLoadStateRequest{
+			DbName:         cfg.DBName,
+			CollectionName: cfg.CollectionName,
+		})
+		if err := merr.CheckRPCCall(loadState, err); err != nil {
+			return 0, err
+		}
+		if loadState.GetState() == commonpb.LoadState_LoadStateLoaded {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return 0, errors.Wrapf(ctx.Err(), "collection %s not loaded, state %s", cfg.CollectionName, loadState.GetState().String())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	describeResp, err := cluster.MilvusClient.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         cfg.DBName,
+		CollectionName: cfg.CollectionName,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return 0, err
+	}
+	return describeResp.GetCollectionID(), nil
+}
+
+// InsertAutoID inserts rowNum rows without primary keys into the auto id collection of the default schema,
+// and returns the primary keys generated for them, which shall be one per row and distinct from each other.
+func (cluster *MiniClusterV2) InsertAutoID(ctx context.Context, dbName, collection string, rowNum, dim int) ([]int64, error) {
+	insertResult, err := cluster.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+		FieldsData:     []*schemapb.FieldData{NewFloatVectorFieldData(FloatVecField, rowNum, dim)},
+		HashKeys:       GenerateHashKeys(rowNum),
+		NumRows:        uint32(rowNum),
+	})
+	if err := merr.CheckRPCCall(insertResult, err); err != nil {
+		return nil, err
+	}
+	pks := insertResult.GetIDs().GetIntId().GetData()
+	if len(pks) != rowNum {
+		return nil, errors.Newf("%d primary keys generated for %d rows inserted into collection %s", len(pks), rowNum, collection)
+	}
+	if duplicates := lo.FindDuplicates(pks); len(duplicates) > 0 {
+		return nil, errors.Newf("duplicate primary keys %v generated for collection %s", duplicates, collection)
+	}
+	return pks, nil
+}
+
+// GetRowCount returns the row count of the collection reported by GetCollectionStatistics,
+// which counts the rows of flushed segments, deletes are not reflected until compaction applies them.
+func (cluster *MiniClusterV2) GetRowCount(ctx context.Context, dbName, collection string) (int64, error) {
+	resp, err := cluster.Proxy.GetCollectionStatistics(ctx, &milvuspb.GetCollectionStatisticsRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return 0, err
+	}
+	for _, kv := range resp.GetStats() {
+		if kv.GetKey() == rowCountKey {
+			return strconv.ParseInt(kv.GetValue(), 10, 64)
+		}
+	}
+	return 0, errors.Newf("%s not found in statistics of collection %s", rowCountKey, collection)
+}
+
+// DropAndRecreateCollection releases and drops the collection named after the schema, then creates it again with the schema.
+// It returns the id of the recreated collection, which must differ from the dropped one.
+func (cluster *MiniClusterV2) DropAndRecreateCollection(ctx context.Context, dbName string, schema *schemapb.CollectionSchema, shardsNum int32) (int64, error) {
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: schema.GetName(),
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return 0, err
+	}
+	droppedID := describeResp.GetCollectionID()
+
+	status, err := cluster.Proxy.ReleaseCollection(ctx, &milvuspb.ReleaseCollectionRequest{
+		DbName:         dbName,
+		CollectionName: schema.GetName(),
+	})
+	if err := merr.CheckRPCCall(status, err); err != nil {
+		return 0, err
+	}
+	status, err = cluster.Proxy.DropCollection(ctx, &milvuspb.DropCollectionRequest{
+		DbName:         dbName,
+		CollectionName: schema.GetName(),
+	})
+	if err := merr.CheckRPCCall(status, err); err != nil {
+		return 0, err
+	}
+
+	marshaledSchema, err := proto.Marshal(schema)
+	if err != nil {
+		return 0, err
+	}
+	status, err = cluster.Proxy.CreateCollection(ctx, &milvuspb.CreateCollectionRequest{
+		DbName:         dbName,
+		CollectionName: schema.GetName(),
+		Schema:         marshaledSchema,
+		ShardsNum:      shardsNum,
+	})
+	if err := merr.CheckRPCCall(status, err); err != nil {
+		return 0, err
+	}
+	describeResp, err = cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: schema.GetName(),
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return 0, err
+	}
+	if describeResp.GetCollectionID() == droppedID {
+		return 0, errors.Newf("recreated collection %s reuses the id %d of the dropped one", schema.GetName(), droppedID)
+	}
+	return describeResp.GetCollectionID(), nil
+}
+
+// VerifyCollectionPKs checks that the loaded collection holds exactly the given int64 primary keys,
+// e.g. no row of a dropped collection with the same name bleeds into it.
+func (cluster *MiniClusterV2) VerifyCollectionPKs(ctx context.Context, dbName, collection, pkField string, pks []int64) error {
+	queryResp, err := cluster.Proxy.Query(ctx, &milvuspb.QueryRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+		OutputFields:   []string{pkField},
+		QueryParams: []*commonpb.KeyValuePair{
+			{Key: LimitKey, Value: strconv.Itoa(len(pks) + 1)},
+		},
+		ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+	})
+	if err := merr.CheckRPCCall(queryResp, err); err != nil {
+		return err
+	}
+	var queried []int64
+	for _, fieldData := range queryResp.GetFieldsData() {
+		if fieldData.GetFieldName() == pkField {
+			queried = fieldData.GetScalars().GetLongData().GetData()
+		}
+	}
+	unexpected, missing := lo.Difference(queried, pks)
+	if len(unexpected) > 0 || len(missing) > 0 {
+		return errors.Newf("primary keys of collection %s mismatch, unexpected: %d, missing: %d", collection, len(unexpected), len(missing))
+	}
+	return nil
+}
+
+// ListAllCollections returns the names of all collections in the default database.
+// ShowCollections is not paged by the server, all names come in one response,
+// so the response is only checked to be self consistent.
+func (cluster *MiniClusterV2) ListAllCollections(ctx context.Context) ([]string, error) {
+	resp, err := cluster.Proxy.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	names := resp.GetCollectionNames()
+	if len(names) != len(resp.GetCollectionIds()) {
+		return nil, errors.Newf("ShowCollections returns %d names but %d ids", len(names), len(resp.GetCollectionIds()))
+	}
+	if len(lo.Uniq(names)) != len(names) {
+		return nil, errors.Newf("ShowCollections returns duplicated names: %v", lo.FindDuplicates(names))
+	}
+	return names, nil
+}
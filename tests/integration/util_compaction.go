@@ -0,0 +1,283 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/metastore/kv/datacoord"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+)
+
+// CompactionTaskInfo is a compaction task reported by datacoord.
+type CompactionTaskInfo struct {
+	PlanID         int64
+	CollectionID   int64
+	Type           datapb.CompactionType
+	State          datapb.CompactionTaskState
+	FailReason     string
+	InputSegments  []int64
+	ResultSegments []int64
+	NodeID         int64
+}
+
+// IsTerminal returns whether the task will never change its state again.
+func (info *CompactionTaskInfo) IsTerminal() bool {
+	switch info.State {
+	case datapb.CompactionTaskState_completed, datapb.CompactionTaskState_failed,
+		datapb.CompactionTaskState_timeout, datapb.CompactionTaskState_cleaned:
+		return true
+	default:
+		return false
+	}
+}
+
+// ListCompactionTasks returns the recent compaction tasks of the collection in the default database from datacoord,
+// finished tasks are kept by datacoord for a while.
+func (cluster *MiniClusterV2) ListCompactionTasks(ctx context.Context, collection string) ([]CompactionTaskInfo, error) {
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return nil, err
+	}
+
+	req, err := metricsinfo.ConstructRequestByMetricType(metricsinfo.CompactionTaskKey)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cluster.DataCoordClient.GetMetrics(ctx, req)
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	var tasks []*metricsinfo.CompactionTask
+	if resp.GetResponse() != "" {
+		if err := json.Unmarshal([]byte(resp.GetResponse()), &tasks); err != nil {
+			return nil, err
+		}
+	}
+
+	var infos []CompactionTaskInfo
+	for _, task := range tasks {
+		if task.CollectionID != describeResp.GetCollectionID() {
+			continue
+		}
+		taskType, ok := datapb.CompactionType_value[task.Type]
+		if !ok {
+			return nil, errors.Newf("unknown type %s of compaction task %d", task.Type, task.PlanID)
+		}
+		state, ok := datapb.CompactionTaskState_value[task.State]
+		if !ok {
+			return nil, errors.Newf("unknown state %s of compaction task %d", task.State, task.PlanID)
+		}
+		inputSegments, err := parseInt64s(task.InputSegments)
+		if err != nil {
+			return nil, err
+		}
+		resultSegments, err := parseInt64s(task.ResultSegments)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, CompactionTaskInfo{
+			PlanID:         task.PlanID,
+			CollectionID:   task.CollectionID,
+			Type:           datapb.CompactionType(taskType),
+			State:          datapb.CompactionTaskState(state),
+			FailReason:     task.FailReason,
+			InputSegments:  inputSegments,
+			ResultSegments: resultSegments,
+			NodeID:         task.NodeID,
+		})
+	}
+	return infos, nil
+}
+
+func parseInt64s(values []string) ([]int64, error) {
+	ret := make([]int64, 0, len(values))
+	for _, value := range values {
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
+
+// CountCompactionTasks returns the number of compaction tasks in each state, keyed by state, read from the meta of datacoord.
+// Tasks waiting for a datanode with free slots are pipelining, while tasks running on datanodes are executing.
+func (cluster *MiniClusterV2) CountCompactionTasks(ctx context.Context) (map[datapb.CompactionTaskState]int, error) {
+	prefix := path.Join(params.EtcdCfg.MetaRootPath.GetValue(), datacoord.CompactionTaskPrefix) + "/"
+	resp, err := cluster.EtcdCli.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[datapb.CompactionTaskState]int)
+	for _, kv := range resp.Kvs {
+		task := &datapb.CompactionTask{}
+		if err := proto.Unmarshal(kv.Value, task); err != nil {
+			return nil, err
+		}
+		counts[task.GetState()]++
+	}
+	return counts, nil
+}
+
+// InsertWithFlushAndCompaction inserts batches of batchSize rows into the loaded collection in the default database
+// continuously for d, while flushing it every flushInterval and compacting it manually every compactInterval at the same time.
+// Once the insertion stops, it waits until the manual compactions are completed,
+// then checks the rows of the collection are exactly the ones before plus the inserted ones, for neither flush nor compaction shall lose any row.
+// It returns the number of rows inserted.
+func (cluster *MiniClusterV2) InsertWithFlushAndCompaction(ctx context.Context, collection string, dim, batchSize int,
+	d, flushInterval, compactInterval time.Duration,
+) (int64, error) {
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return 0, err
+	}
+	before, err := cluster.QueryCount(ctx, collection, "")
+	if err != nil {
+		return 0, err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	var (
+		wg                      sync.WaitGroup
+		inserted                int64
+		insertErr, flushErr     error
+		compactionIDs           []int64
+		flushCount, compactions int
+	)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for runCtx.Err() == nil {
+			insertResult, err := cluster.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+				CollectionName: collection,
+				FieldsData:     []*schemapb.FieldData{NewFloatVectorFieldData(FloatVecField, batchSize, dim)},
+				HashKeys:       GenerateHashKeys(batchSize),
+				NumRows:        uint32(batchSize),
+			})
+			if err := merr.CheckRPCCall(insertResult, err); err != nil {
+				insertErr = err
+				return
+			}
+			inserted += insertResult.GetInsertCnt()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			flushResp, err := cluster.Proxy.Flush(ctx, &milvuspb.FlushRequest{
+				CollectionNames: []string{collection},
+			})
+			if err := merr.CheckRPCCall(flushResp, err); err != nil {
+				flushErr = err
+				return
+			}
+			flushCount++
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(compactInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			compactResp, err := cluster.Proxy.ManualCompaction(ctx, &milvuspb.ManualCompactionRequest{
+				CollectionID: describeResp.GetCollectionID(),
+			})
+			// a compaction is allowed to be rejected, e.g. while the previous one is still running
+			if err := merr.CheckRPCCall(compactResp, err); err != nil {
+				log.Info("manual compaction rejected", zap.String("collection", collection), zap.Error(err))
+				continue
+			}
+			compactions++
+			if compactResp.GetCompactionID() > 0 {
+				compactionIDs = append(compactionIDs, compactResp.GetCompactionID())
+			}
+		}
+	}()
+	wg.Wait()
+	if insertErr != nil {
+		return inserted, errors.Wrap(insertErr, "insert failed while flushing and compacting")
+	}
+	if flushErr != nil {
+		return inserted, errors.Wrap(flushErr, "flush failed while inserting and compacting")
+	}
+	log.Info("insertion with flush and compaction stopped", zap.String("collection", collection),
+		zap.Int64("inserted", inserted), zap.Int("flushes", flushCount), zap.Int("compactions", compactions))
+
+	for _, compactionID := range compactionIDs {
+		for {
+			stateResp, err := cluster.Proxy.GetCompactionState(ctx, &milvuspb.GetCompactionStateRequest{
+				CompactionID: compactionID,
+			})
+			if err := merr.CheckRPCCall(stateResp, err); err != nil {
+				return inserted, err
+			}
+			if stateResp.GetState() == commonpb.CompactionState_Completed {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return inserted, errors.Wrapf(ctx.Err(), "compaction %d of collection %s not completed", compactionID, collection)
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	}
+
+	count, err := cluster.QueryCount(ctx, collection, "")
+	if err != nil {
+		return inserted, err
+	}
+	if count != before+inserted {
+		return inserted, errors.Newf("collection %s has %d rows after flush and compaction, expected %d rows, %d of which are inserted",
+			collection, count, before+inserted, inserted)
+	}
+	return inserted, nil
+}
@@ -0,0 +1,44 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+)
+
+// UpdateConfig overrides the config of key by value in the running cluster without restart,
+// the watchers of key registered by the components are notified as if the key is changed in the config sources.
+//
+// Whether a component picks up the new value depends on how it reads the config:
+//   - configs read at use time, e.g. dataCoord.enableAutoCompaction or the quota and rate limit configs,
+//     take effect from the next read on.
+//   - configs cached by components are reloaded only if the components watch them,
+//     e.g. queryCoord.clusterLevelLoadReplicaNumber, dataNode.dataSync.maxParallelSyncMgrTasks or proxy.accessLog.enable.
+//   - configs read once on start, e.g. ports, intervals of the background loops or the sizes of pools nobody resizes,
+//     keep their value until the component is restarted.
+//
+// Configs forbidden to be updated at runtime, e.g. msgChannel.chanNamePrefix.cluster, are rejected.
+// The updated configs are not reset on Stop, tests shall restore them once done.
+func (cluster *MiniClusterV2) UpdateConfig(key, value string) error {
+	if err := params.Refresh(key, value); err != nil {
+		return err
+	}
+	log.Info("mini cluster config updated", zap.String("key", key), zap.String("value", value))
+	return nil
+}
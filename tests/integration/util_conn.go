@@ -0,0 +1,111 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// OpenConnections dials the proxy n times at the same time, and issues a GetComponentStates on each connection.
+// All connections are closed if any of them fails, otherwise they shall be closed by CloseConnections.
+func (cluster *MiniClusterV2) OpenConnections(n int) ([]*grpc.ClientConn, error) {
+	addr := fmt.Sprintf("localhost:%d", params.ProxyGrpcServerCfg.Port.GetAsInt())
+	conns := make([]*grpc.ClientConn, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			conn, err := grpc.DialContext(cluster.ctx, addr, cluster.getGrpcDialOpt()...)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			conns[i] = conn
+			states, err := milvuspb.NewMilvusServiceClient(conn).GetComponentStates(cluster.ctx, &milvuspb.GetComponentStatesRequest{})
+			if err := merr.CheckRPCCall(states, err); err != nil {
+				errs[i] = err
+				return
+			}
+			if code := states.GetState().GetStateCode(); code != commonpb.StateCode_Healthy {
+				errs[i] = errors.Newf("proxy is %s on connection %d", code.String(), i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := merr.Combine(errs...); err != nil {
+		CloseConnections(conns)
+		return nil, err
+	}
+	return conns, nil
+}
+
+// CloseConnections closes the connections opened by OpenConnections.
+func CloseConnections(conns []*grpc.ClientConn) {
+	for _, conn := range conns {
+		if conn != nil {
+			conn.Close()
+		}
+	}
+}
+
+// newClientTLSCredentials loads the credentials for the clients of WithTLS,
+// which present the certificate of certFile and keyFile and verify proxy by the ca of caFile.
+func newClientTLSCredentials(certFile, keyFile, caFile string) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load tls certificate")
+	}
+	ca, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read tls ca")
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(ca) {
+		return nil, errors.Newf("no certificate found in tls ca %s", caFile)
+	}
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      certPool,
+		ServerName:   "localhost",
+		MinVersion:   tls.VersionTLS13,
+	}), nil
+}
+
+// getTransportCredentials returns the transport credentials of the clients dialing proxy, the ones of WithTLS if set.
+func (cluster *MiniClusterV2) getTransportCredentials() credentials.TransportCredentials {
+	if cluster.tlsCreds != nil {
+		return cluster.tlsCreds
+	}
+	return insecure.NewCredentials()
+}
@@ -0,0 +1,158 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// DeleteAndReinsert deletes the rows of the int64 primary keys, then inserts fieldsData carrying the same keys.
+func (cluster *MiniClusterV2) DeleteAndReinsert(ctx context.Context, dbName, collection, pkField string, pks []int64, fieldsData []*schemapb.FieldData) error {
+	expr := fmt.Sprintf("%s in [%s]", pkField, strings.Join(lo.Map(pks, func(pk int64, _ int) string {
+		return fmt.Sprint(pk)
+	}), ","))
+	deleteResult, err := cluster.Proxy.Delete(ctx, &milvuspb.DeleteRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+		Expr:           expr,
+	})
+	if err := merr.CheckRPCCall(deleteResult, err); err != nil {
+		return err
+	}
+	if deleteResult.GetDeleteCnt() != int64(len(pks)) {
+		return errors.Newf("unexpected delete count, expected: %d, actual: %d", len(pks), deleteResult.GetDeleteCnt())
+	}
+
+	insertResult, err := cluster.Proxy.Insert(ctx, &milvuspb.InsertRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+		FieldsData:     fieldsData,
+		HashKeys:       GenerateHashKeys(len(pks)),
+		NumRows:        uint32(len(pks)),
+	})
+	return merr.CheckRPCCall(insertResult, err)
+}
+
+// CheckRowByPK checks that exactly one row of the int64 primary key is visible,
+// and its float vector of vecField equals the expected one.
+func (cluster *MiniClusterV2) CheckRowByPK(ctx context.Context, dbName, collection, pkField string, pk int64, vecField string, expected []float32) error {
+	queryResult, err := cluster.Proxy.Query(ctx, &milvuspb.QueryRequest{
+		DbName:           dbName,
+		CollectionName:   collection,
+		Expr:             fmt.Sprintf("%s == %d", pkField, pk),
+		OutputFields:     []string{pkField, vecField},
+		ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+	})
+	if err := merr.CheckRPCCall(queryResult, err); err != nil {
+		return err
+	}
+
+	var pks []int64
+	var vectors []float32
+	for _, fieldData := range queryResult.GetFieldsData() {
+		switch fieldData.GetFieldName() {
+		case pkField:
+			pks = fieldData.GetScalars().GetLongData().GetData()
+		case vecField:
+			vectors = fieldData.GetVectors().GetFloatVector().GetData()
+		}
+	}
+	if len(pks) != 1 {
+		return errors.Newf("expected exactly one row of pk %d, got %d", pk, len(pks))
+	}
+	if !slices.Equal(expected, vectors) {
+		return errors.Newf("vector of pk %d mismatched, expected: %v, actual: %v", pk, expected, vectors)
+	}
+	return nil
+}
+
+// DeleteByPKs deletes the rows of the int64 primary keys from the collection in the default database.
+// The keys are sent as a template value of the delete expr rather than spelled out in it,
+// and every one of them shall be deleted.
+func (cluster *MiniClusterV2) DeleteByPKs(ctx context.Context, collection string, pks []int64) (*milvuspb.MutationResult, error) {
+	pkField, err := cluster.int64PKField(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	deleteResult, err := cluster.Proxy.Delete(ctx, &milvuspb.DeleteRequest{
+		CollectionName: collection,
+		Expr:           fmt.Sprintf("%s in {pks}", pkField),
+		ExprTemplateValues: map[string]*schemapb.TemplateValue{
+			"pks": {
+				Val: &schemapb.TemplateValue_ArrayVal{
+					ArrayVal: &schemapb.TemplateArrayValue{
+						Data: &schemapb.TemplateArrayValue_LongData{
+							LongData: &schemapb.LongArray{Data: pks},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err := merr.CheckRPCCall(deleteResult, err); err != nil {
+		return nil, err
+	}
+	if deleteResult.GetDeleteCnt() != int64(len(pks)) {
+		return deleteResult, errors.Newf("unexpected delete count, expected: %d, actual: %d", len(pks), deleteResult.GetDeleteCnt())
+	}
+	return deleteResult, nil
+}
+
+// VerifyPKsDeleted checks none of the int64 primary keys is visible in the collection in the default database.
+func (cluster *MiniClusterV2) VerifyPKsDeleted(ctx context.Context, collection string, pks []int64) error {
+	pkField, err := cluster.int64PKField(ctx, collection)
+	if err != nil {
+		return err
+	}
+	count, err := cluster.QueryCount(ctx, collection, fmt.Sprintf("%s in [%s]", pkField, strings.Join(lo.Map(pks, func(pk int64, _ int) string {
+		return fmt.Sprint(pk)
+	}), ",")))
+	if err != nil {
+		return err
+	}
+	if count != 0 {
+		return errors.Newf("%d of %d deleted primary keys are still visible in collection %s", count, len(pks), collection)
+	}
+	return nil
+}
+
+func (cluster *MiniClusterV2) int64PKField(ctx context.Context, collection string) (string, error) {
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return "", err
+	}
+	pkField, ok := lo.Find(describeResp.GetSchema().GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetIsPrimaryKey()
+	})
+	if !ok || pkField.GetDataType() != schemapb.DataType_Int64 {
+		return "", errors.Newf("collection %s has no int64 primary key", collection)
+	}
+	return pkField.GetName(), nil
+}
@@ -0,0 +1,186 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+)
+
+const latencyProxyBufferSize = 32 * 1024
+
+// etcdLatencyProxy is a tcp proxy in front of etcd, which delays every chunk sent to etcd by the latency.
+// Chunks are delayed independently, so the latency doesn't throttle the throughput of the connection.
+type etcdLatencyProxy struct {
+	listener net.Listener
+	target   string
+	latency  atomic.Duration
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newEtcdLatencyProxy(target string, latency time.Duration) (*etcdLatencyProxy, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return nil, err
+	}
+	p := &etcdLatencyProxy{
+		listener: listener,
+		target:   target,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	p.latency.Store(latency)
+	p.wg.Add(1)
+	go p.serve()
+	log.Info("etcd latency proxy started", zap.String("addr", p.Addr()), zap.String("target", target), zap.Duration("latency", latency))
+	return p, nil
+}
+
+func (p *etcdLatencyProxy) Addr() string {
+	return p.listener.Addr().String()
+}
+
+func (p *etcdLatencyProxy) serve() {
+	defer p.wg.Done()
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+		upstream, err := net.Dial("tcp", p.target)
+		if err != nil {
+			log.Warn("etcd latency proxy fail to dial etcd", zap.String("target", p.target), zap.Error(err))
+			conn.Close()
+			continue
+		}
+		if !p.track(conn, upstream) {
+			conn.Close()
+			upstream.Close()
+			return
+		}
+		p.wg.Add(2)
+		go p.pipe(upstream, conn, true)
+		go p.pipe(conn, upstream, false)
+	}
+}
+
+func (p *etcdLatencyProxy) track(conns ...net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	for _, conn := range conns {
+		p.conns[conn] = struct{}{}
+	}
+	return true
+}
+
+type delayedChunk struct {
+	data     []byte
+	deadline time.Time
+}
+
+// pipe copies src to dst, chunks are held until their deadline if delayed is set.
+func (p *etcdLatencyProxy) pipe(dst, src net.Conn, delayed bool) {
+	defer p.wg.Done()
+	defer dst.Close()
+	defer src.Close()
+	if !delayed {
+		io.Copy(dst, src)
+		return
+	}
+
+	chunks := make(chan delayedChunk, 1024)
+	go func() {
+		defer close(chunks)
+		for {
+			buf := make([]byte, latencyProxyBufferSize)
+			n, err := src.Read(buf)
+			if n > 0 {
+				chunks <- delayedChunk{data: buf[:n], deadline: time.Now().Add(p.latency.Load())}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	for chunk := range chunks {
+		time.Sleep(time.Until(chunk.deadline))
+		if _, err := dst.Write(chunk.data); err != nil {
+			// unblock the reader and drain the pending chunks
+			src.Close()
+			for range chunks {
+			}
+			return
+		}
+	}
+}
+
+func (p *etcdLatencyProxy) SetLatency(latency time.Duration) {
+	p.latency.Store(latency)
+	log.Info("etcd latency proxy latency changed", zap.Duration("latency", latency))
+}
+
+func (p *etcdLatencyProxy) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.listener.Close()
+	for conn := range p.conns {
+		conn.Close()
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+// SetEtcdLatency changes the latency of the etcd proxy of WithEtcdLatency at runtime,
+// the requests sent to etcd afterwards are delayed by d, while the ones already in flight keep their delay.
+func (cluster *MiniClusterV2) SetEtcdLatency(d time.Duration) error {
+	if cluster.etcdProxy == nil {
+		return errors.New("etcd latency can only be changed on a cluster started WithEtcdLatency")
+	}
+	cluster.etcdProxy.SetLatency(d)
+	return nil
+}
+
+// CompactEtcd compacts away all revisions of etcd before the current one and returns the compacted revision,
+// watches of the cluster resuming from a compacted revision fail with ErrCompacted and have to rewatch.
+func (cluster *MiniClusterV2) CompactEtcd(ctx context.Context) (int64, error) {
+	resp, err := cluster.EtcdCli.Get(ctx, "/", clientv3.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+	revision := resp.Header.GetRevision()
+	if _, err := cluster.EtcdCli.Compact(ctx, revision, clientv3.WithCompactPhysical()); err != nil {
+		return 0, err
+	}
+	log.Info("etcd compacted", zap.Int64("revision", revision))
+	return revision, nil
+}
@@ -0,0 +1,110 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+const (
+	exportBatchSize = 1000
+
+	iteratorKey          = "iterator"
+	reduceStopForBestKey = "reduce_stop_for_best"
+)
+
+// ExportCollection pages all rows of a loaded collection in primary key order via query iterator,
+// and writes every page as a json file named by the page number under dir of the ChunkManager.
+// It returns the number of exported rows, only int64 primary key is supported.
+func (cluster *MiniClusterV2) ExportCollection(ctx context.Context, collection, dir string) (int64, error) {
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return 0, err
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(describeResp.GetSchema())
+	if err != nil {
+		return 0, err
+	}
+	if pkField.GetDataType() != schemapb.DataType_Int64 {
+		return 0, errors.Newf("export only supports int64 primary key, got %s", pkField.GetDataType().String())
+	}
+
+	var total int64
+	expr := ""
+	for page := 0; ; page++ {
+		queryResp, err := cluster.Proxy.Query(ctx, &milvuspb.QueryRequest{
+			CollectionName: collection,
+			Expr:           expr,
+			OutputFields:   []string{"*"},
+			QueryParams: []*commonpb.KeyValuePair{
+				{Key: LimitKey, Value: strconv.Itoa(exportBatchSize)},
+				{Key: iteratorKey, Value: "true"},
+				{Key: reduceStopForBestKey, Value: "false"},
+			},
+			ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+		})
+		if err := merr.CheckRPCCall(queryResp, err); err != nil {
+			return total, err
+		}
+
+		var pks []int64
+		for _, fieldData := range queryResp.GetFieldsData() {
+			if fieldData.GetFieldName() == pkField.GetName() {
+				pks = fieldData.GetScalars().GetLongData().GetData()
+			}
+		}
+		if len(pks) == 0 {
+			return total, nil
+		}
+
+		rows := make([]map[string]any, len(pks))
+		for i := range rows {
+			rows[i] = make(map[string]any, len(queryResp.GetFieldsData()))
+			for _, fieldData := range queryResp.GetFieldsData() {
+				rows[i][fieldData.GetFieldName()] = typeutil.GetData(fieldData, i)
+			}
+		}
+		bs, err := json.Marshal(rows)
+		if err != nil {
+			return total, err
+		}
+		filePath := path.Join(cluster.ChunkManager.RootPath(), dir, fmt.Sprintf("%d.json", page))
+		if err := cluster.ChunkManager.Write(ctx, filePath, bs); err != nil {
+			return total, err
+		}
+		total += int64(len(pks))
+
+		if len(pks) < exportBatchSize {
+			return total, nil
+		}
+		expr = fmt.Sprintf("%s > %d", pkField.GetName(), pks[len(pks)-1])
+	}
+}
@@ -0,0 +1,111 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// HealthUnavailable is the health CheckHealthAll reports for a component which is stopped or doesn't respond.
+const HealthUnavailable = "Unavailable"
+
+// componentStater is a server which reports its state by GetComponentStates.
+type componentStater interface {
+	GetComponentStates(ctx context.Context, req *milvuspb.GetComponentStatesRequest) (*milvuspb.ComponentStates, error)
+}
+
+// CheckHealthAll asks every component of the cluster for its state, including the extra nodes and proxies,
+// and returns whether all of them are healthy along with the state of each, keyed by the same names as Stop logs them,
+// e.g. rootCoord or extra queryNode 0. A stopped component, e.g. after StopRootCoord, is reported as HealthUnavailable.
+// The error is only returned once ctx is done, after which the states are not reliable.
+func (cluster *MiniClusterV2) CheckHealthAll(ctx context.Context) (bool, map[string]string, error) {
+	health := make(map[string]string)
+	check := func(name string, server componentStater) {
+		states, err := server.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+		if err := merr.CheckRPCCall(states, err); err != nil {
+			health[name] = HealthUnavailable
+			return
+		}
+		health[name] = states.GetState().GetStateCode().String()
+	}
+	unavailable := func(name string) {
+		health[name] = HealthUnavailable
+	}
+
+	if cluster.RootCoord != nil {
+		check("rootCoord", cluster.RootCoord)
+	} else {
+		unavailable("rootCoord")
+	}
+	if cluster.DataCoord != nil {
+		check("dataCoord", cluster.DataCoord)
+	} else {
+		unavailable("dataCoord")
+	}
+	if cluster.QueryCoord != nil {
+		check("queryCoord", cluster.QueryCoord)
+	} else {
+		unavailable("queryCoord")
+	}
+	if cluster.Proxy != nil {
+		check("proxy", cluster.Proxy)
+	} else {
+		unavailable("proxy")
+	}
+	for i, node := range cluster.proxies {
+		check(fmt.Sprintf("extra proxy %d", i), node)
+	}
+	if cluster.DataNode != nil {
+		check("main dataNode", cluster.DataNode)
+	} else {
+		unavailable("main dataNode")
+	}
+	for i, node := range cluster.datanodes {
+		check(fmt.Sprintf("extra dataNode %d", i), node)
+	}
+	if cluster.QueryNode != nil {
+		check("main queryNode", cluster.QueryNode)
+	} else {
+		unavailable("main queryNode")
+	}
+	for i, node := range cluster.querynodes {
+		check(fmt.Sprintf("extra queryNode %d", i), node)
+	}
+	// streamingnodes only run with the streaming service enabled
+	if cluster.StreamingNode != nil {
+		health["main streamingnode"] = cluster.StreamingNode.Health(ctx).String()
+	}
+	for i, node := range cluster.streamingnodes {
+		health[fmt.Sprintf("extra streamingnode %d", i)] = node.Health(ctx).String()
+	}
+
+	if ctx.Err() != nil {
+		return false, health, ctx.Err()
+	}
+	healthy := true
+	for _, state := range health {
+		if state != commonpb.StateCode_Healthy.String() {
+			healthy = false
+		}
+	}
+	return healthy, health, nil
+}
@@ -23,9 +23,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 )
 
 const (
@@ -42,83 +46,137 @@ const (
 	IndexDISKANN             = "DISKANN"
 	IndexSparseInvertedIndex = "SPARSE_INVERTED_INDEX"
 	IndexSparseWand          = "SPARSE_WAND"
+
+	IndexInverted = "INVERTED"
+	IndexSTLSort  = "STL_SORT"
 )
 
-func (s *MiniClusterSuite) WaitForIndexBuiltWithDB(ctx context.Context, dbName, collection, field string) {
-	s.waitForIndexBuiltInternal(ctx, dbName, collection, field, "")
+// DescribeFieldIndexes returns the index descriptions of the collection keyed by field name.
+func (cluster *MiniClusterV2) DescribeFieldIndexes(ctx context.Context, dbName, collection string) (map[string]*milvuspb.IndexDescription, error) {
+	resp, err := cluster.Proxy.DescribeIndex(ctx, &milvuspb.DescribeIndexRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	descs := make(map[string]*milvuspb.IndexDescription, len(resp.GetIndexDescriptions()))
+	for _, desc := range resp.GetIndexDescriptions() {
+		descs[desc.GetFieldName()] = desc
+	}
+	return descs, nil
 }
 
-func (s *MiniClusterSuite) WaitForIndexBuilt(ctx context.Context, collection, field string) {
-	s.waitForIndexBuiltInternal(ctx, "", collection, field, "")
+// IndexStats is the index statistics of a field reported by GetIndexStatistics.
+type IndexStats struct {
+	IndexName        string
+	State            commonpb.IndexState
+	IndexedRows      int64
+	PendingIndexRows int64
+	TotalRows        int64
 }
 
-func (s *MiniClusterSuite) WaitForIndexBuiltWithIndexName(ctx context.Context, collection, field, indexName string) {
-	s.waitForIndexBuiltInternal(ctx, "", collection, field, indexName)
+// GetIndexStats returns the index statistics of the field of the collection in the default database.
+func (cluster *MiniClusterV2) GetIndexStats(ctx context.Context, collection, field string) (IndexStats, error) {
+	resp, err := cluster.Proxy.GetIndexStatistics(ctx, &milvuspb.GetIndexStatisticsRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return IndexStats{}, err
+	}
+	for _, desc := range resp.GetIndexDescriptions() {
+		if desc.GetFieldName() == field {
+			return IndexStats{
+				IndexName:        desc.GetIndexName(),
+				State:            desc.GetState(),
+				IndexedRows:      desc.GetIndexedRows(),
+				PendingIndexRows: desc.GetPendingIndexRows(),
+				TotalRows:        desc.GetTotalRows(),
+			}, nil
+		}
+	}
+	return IndexStats{}, errors.Newf("field %s of collection %s has no index", field, collection)
 }
 
-func (s *MiniClusterSuite) waitForIndexBuiltInternal(ctx context.Context, dbName, collection, field, indexName string) {
-	getIndexBuilt := func() bool {
-		resp, err := s.Cluster.Proxy.DescribeIndex(ctx, &milvuspb.DescribeIndexRequest{
+// CheckIndexStats checks every row of the flushed segments is either indexed or pending.
+// Note that the state of the index may stay finished while segments flushed after the index is created are pending.
+func CheckIndexStats(stats IndexStats) error {
+	if stats.IndexedRows+stats.PendingIndexRows != stats.TotalRows {
+		return errors.Newf("index %s has %d indexed rows and %d pending rows, which don't add up to %d total rows",
+			stats.IndexName, stats.IndexedRows, stats.PendingIndexRows, stats.TotalRows)
+	}
+	return nil
+}
+
+// WaitForIndexBuilt waits until every index on the field of the collection is built,
+// that is all rows of the flushed segments are indexed, including the ones flushed after the index is created.
+// It fails at once if the field has no index or any of its indexes fails to build.
+func (cluster *MiniClusterV2) WaitForIndexBuilt(ctx context.Context, dbName, collectionName, fieldName string) error {
+	return cluster.waitForIndexBuilt(ctx, dbName, collectionName, fieldName, "")
+}
+
+// waitForIndexBuilt implements WaitForIndexBuilt, only the index of indexName is waited for if it's not empty.
+func (cluster *MiniClusterV2) waitForIndexBuilt(ctx context.Context, dbName, collectionName, fieldName, indexName string) error {
+	for {
+		resp, err := cluster.Proxy.DescribeIndex(ctx, &milvuspb.DescribeIndexRequest{
 			DbName:         dbName,
-			CollectionName: collection,
-			FieldName:      field,
+			CollectionName: collectionName,
 			IndexName:      indexName,
 		})
-		if err != nil {
-			s.FailNow("failed to describe index")
-			return true
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			if errors.Is(err, merr.ErrIndexNotFound) {
+				return errors.Wrapf(err, "field %s of collection %s has no index", fieldName, collectionName)
+			}
+			return err
+		}
+		descs := lo.Filter(resp.GetIndexDescriptions(), func(desc *milvuspb.IndexDescription, _ int) bool {
+			return desc.GetFieldName() == fieldName
+		})
+		if len(descs) == 0 {
+			return errors.Newf("field %s of collection %s has no index", fieldName, collectionName)
 		}
-		for _, desc := range resp.GetIndexDescriptions() {
-			if desc.GetFieldName() == field {
-				switch desc.GetState() {
-				case commonpb.IndexState_Finished:
-					return true
-				case commonpb.IndexState_Failed:
-					return false
-				}
+		built := true
+		for _, desc := range descs {
+			if desc.GetState() == commonpb.IndexState_Failed {
+				return errors.Newf("index %s on field %s of collection %s failed to build, reason: %s",
+					desc.GetIndexName(), fieldName, collectionName, desc.GetIndexStateFailReason())
+			}
+			if desc.GetState() != commonpb.IndexState_Finished || desc.GetIndexedRows() != desc.GetTotalRows() {
+				built = false
 			}
 		}
-		return false
-	}
-	for !getIndexBuilt() {
+		if built {
+			return nil
+		}
 		select {
 		case <-ctx.Done():
-			s.FailNow("failed to wait index built until ctx done")
-			return
+			return errors.Wrapf(ctx.Err(), "index on field %s of collection %s not built", fieldName, collectionName)
 		case <-time.After(500 * time.Millisecond):
 		}
 	}
 }
 
-func waitingForIndexBuilt(ctx context.Context, cluster *MiniClusterV2, t *testing.T, collection, field string) {
-	getIndexBuilt := func() bool {
-		resp, err := cluster.Proxy.DescribeIndex(ctx, &milvuspb.DescribeIndexRequest{
-			CollectionName: collection,
-			FieldName:      field,
-		})
-		if err != nil {
-			t.FailNow()
-			return true
-		}
-		for _, desc := range resp.GetIndexDescriptions() {
-			if desc.GetFieldName() == field {
-				switch desc.GetState() {
-				case commonpb.IndexState_Finished:
-					return true
-				case commonpb.IndexState_Failed:
-					return false
-				}
-			}
-		}
-		return false
+func (s *MiniClusterSuite) WaitForIndexBuiltWithDB(ctx context.Context, dbName, collection, field string) {
+	s.waitForIndexBuiltInternal(ctx, dbName, collection, field, "")
+}
+
+func (s *MiniClusterSuite) WaitForIndexBuilt(ctx context.Context, collection, field string) {
+	s.waitForIndexBuiltInternal(ctx, "", collection, field, "")
+}
+
+func (s *MiniClusterSuite) WaitForIndexBuiltWithIndexName(ctx context.Context, collection, field, indexName string) {
+	s.waitForIndexBuiltInternal(ctx, "", collection, field, indexName)
+}
+
+func (s *MiniClusterSuite) waitForIndexBuiltInternal(ctx context.Context, dbName, collection, field, indexName string) {
+	if err := s.Cluster.waitForIndexBuilt(ctx, dbName, collection, field, indexName); err != nil {
+		s.FailNow("failed to wait index built", err.Error())
 	}
-	for !getIndexBuilt() {
-		select {
-		case <-ctx.Done():
-			t.FailNow()
-			return
-		case <-time.After(500 * time.Millisecond):
-		}
+}
+
+func waitingForIndexBuilt(ctx context.Context, cluster *MiniClusterV2, t *testing.T, collection, field string) {
+	if err := cluster.WaitForIndexBuilt(ctx, "", collection, field); err != nil {
+		t.Fatalf("failed to wait index built: %v", err)
 	}
 }
 
@@ -176,6 +234,22 @@ func ConstructIndexParam(dim int, indexType string, metricType string) []*common
 	return params
 }
 
+// ConstructScalarIndexParam returns the params to build a scalar index of the type on a scalar field.
+func ConstructScalarIndexParam(indexType string) []*commonpb.KeyValuePair {
+	switch indexType {
+	case IndexInverted, IndexSTLSort:
+	// no index param is required
+	default:
+		panic(fmt.Sprintf("unimplemented scalar index param for %s, please help to improve it", indexType))
+	}
+	return []*commonpb.KeyValuePair{
+		{
+			Key:   common.IndexTypeKey,
+			Value: indexType,
+		},
+	}
+}
+
 func GetSearchParams(indexType string, metricType string) map[string]any {
 	params := make(map[string]any)
 	switch indexType {
@@ -18,11 +18,21 @@ package integration
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/rootcoordpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/parameterutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/testutils"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
 )
 
 func (s *MiniClusterSuite) WaitForFlush(ctx context.Context, segIDs []int64, flushTs uint64, dbName, collectionName string) {
@@ -49,6 +59,199 @@ func (s *MiniClusterSuite) WaitForFlush(ctx context.Context, segIDs []int64, flu
 	}
 }
 
+// WaitForAllFlushed waits until all the data inserted into the collections of the default database so far is flushed,
+// that is the checkpoints of all their channels pass the current timestamp.
+// It's meant to be called after the collections are flushed, e.g. by FlushAll.
+func (cluster *MiniClusterV2) WaitForAllFlushed(ctx context.Context, collections []string) error {
+	tsResp, err := cluster.RootCoordClient.AllocTimestamp(ctx, &rootcoordpb.AllocTimestampRequest{Count: 1})
+	if err := merr.CheckRPCCall(tsResp, err); err != nil {
+		return err
+	}
+	flushTs := tsResp.GetTimestamp()
+
+	for _, collection := range collections {
+		for {
+			resp, err := cluster.Proxy.GetFlushState(ctx, &milvuspb.GetFlushStateRequest{
+				FlushTs:        flushTs,
+				CollectionName: collection,
+			})
+			if err := merr.CheckRPCCall(resp, err); err != nil {
+				return err
+			}
+			if resp.GetFlushed() {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return errors.Wrapf(ctx.Err(), "collection %s not flushed", collection)
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	}
+	return nil
+}
+
+// FlushAndWait flushes the collection of the default database by MilvusClient, and waits until every segment sealed by the flush
+// is flushed, or compacted into others after flushed. It returns the ids of the segments sealed by the flush,
+// and the ones still pending once ctx is done along with the error.
+func (cluster *MiniClusterV2) FlushAndWait(ctx context.Context, collectionName string) ([]int64, error) {
+	describeResp, err := cluster.MilvusClient.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collectionName,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return nil, err
+	}
+	flushResp, err := cluster.MilvusClient.Flush(ctx, &milvuspb.FlushRequest{
+		CollectionNames: []string{collectionName},
+	})
+	if err := merr.CheckRPCCall(flushResp, err); err != nil {
+		return nil, err
+	}
+	segmentIDs := flushResp.GetCollSegIDs()[collectionName].GetData()
+
+	for {
+		segments, err := cluster.MetaWatcher.ListSegments(describeResp.GetCollectionID())
+		if err != nil {
+			return nil, err
+		}
+		states := make(map[int64]*datapb.SegmentInfo, len(segments))
+		for _, segment := range segments {
+			states[segment.GetID()] = segment
+		}
+		pending := lo.Filter(segmentIDs, func(id int64, _ int) bool {
+			segment, ok := states[id]
+			return !ok || !(segment.GetState() == commonpb.SegmentState_Flushed ||
+				segment.GetState() == commonpb.SegmentState_Dropped && segment.GetCompacted())
+		})
+		if len(pending) == 0 {
+			return segmentIDs, nil
+		}
+		select {
+		case <-ctx.Done():
+			return pending, errors.Wrapf(ctx.Err(), "segments %v of collection %s not flushed", pending, collectionName)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// InsertRandomData inserts rows generated from seed into the collection of the default database by MilvusClient,
+// the fields are inferred from the schema of the collection, so the same seed generates the same data for the same schema.
+// Bool, int32, int64, float, double, varchar and float vector fields are supported, while the primary key with auto id,
+// the dynamic field and the output fields of functions are left to the server.
+// The primary keys of the inserted rows are returned in the mutation result.
+func (cluster *MiniClusterV2) InsertRandomData(ctx context.Context, collectionName string, rows int, seed int64) (*milvuspb.MutationResult, error) {
+	describeResp, err := cluster.MilvusClient.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collectionName,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return nil, err
+	}
+	r := rand.New(rand.NewSource(seed))
+	fieldsData := make([]*schemapb.FieldData, 0, len(describeResp.GetSchema().GetFields()))
+	for _, field := range describeResp.GetSchema().GetFields() {
+		if (field.GetIsPrimaryKey() && field.GetAutoID()) || field.GetIsDynamic() || field.GetIsFunctionOutput() {
+			continue
+		}
+		fieldData, err := generateRandomFieldData(r, field, rows)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate data of collection %s", collectionName)
+		}
+		fieldsData = append(fieldsData, fieldData)
+	}
+
+	insertResp, err := cluster.MilvusClient.Insert(ctx, &milvuspb.InsertRequest{
+		CollectionName: collectionName,
+		FieldsData:     fieldsData,
+		HashKeys:       GenerateHashKeys(rows),
+		NumRows:        uint32(rows),
+	})
+	if err := merr.CheckRPCCall(insertResp, err); err != nil {
+		return nil, err
+	}
+	return insertResp, nil
+}
+
+const randomStringLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// generateRandomFieldData generates rows of the field from r, the values of nullable fields are all valid.
+func generateRandomFieldData(r *rand.Rand, field *schemapb.FieldSchema, rows int) (*schemapb.FieldData, error) {
+	fieldData := &schemapb.FieldData{
+		Type:      field.GetDataType(),
+		FieldName: field.GetName(),
+	}
+	scalars := func(data *schemapb.ScalarField) {
+		fieldData.Field = &schemapb.FieldData_Scalars{Scalars: data}
+	}
+	switch field.GetDataType() {
+	case schemapb.DataType_Bool:
+		data := make([]bool, rows)
+		for i := range data {
+			data[i] = r.Intn(2) == 1
+		}
+		scalars(&schemapb.ScalarField{Data: &schemapb.ScalarField_BoolData{BoolData: &schemapb.BoolArray{Data: data}}})
+	case schemapb.DataType_Int32:
+		data := make([]int32, rows)
+		for i := range data {
+			data[i] = r.Int31()
+		}
+		scalars(&schemapb.ScalarField{Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: data}}})
+	case schemapb.DataType_Int64:
+		data := make([]int64, rows)
+		for i := range data {
+			data[i] = r.Int63()
+		}
+		scalars(&schemapb.ScalarField{Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: data}}})
+	case schemapb.DataType_Float:
+		data := make([]float32, rows)
+		for i := range data {
+			data[i] = r.Float32()
+		}
+		scalars(&schemapb.ScalarField{Data: &schemapb.ScalarField_FloatData{FloatData: &schemapb.FloatArray{Data: data}}})
+	case schemapb.DataType_Double:
+		data := make([]float64, rows)
+		for i := range data {
+			data[i] = r.Float64()
+		}
+		scalars(&schemapb.ScalarField{Data: &schemapb.ScalarField_DoubleData{DoubleData: &schemapb.DoubleArray{Data: data}}})
+	case schemapb.DataType_VarChar:
+		maxLength, err := parameterutil.GetMaxLength(field)
+		if err != nil {
+			return nil, err
+		}
+		data := make([]string, rows)
+		for i := range data {
+			// long enough to keep random primary keys distinct
+			b := make([]byte, min(maxLength, 16))
+			for j := range b {
+				b[j] = randomStringLetters[r.Intn(len(randomStringLetters))]
+			}
+			data[i] = string(b)
+		}
+		scalars(&schemapb.ScalarField{Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: data}}})
+	case schemapb.DataType_FloatVector:
+		dim, err := typeutil.GetDim(field)
+		if err != nil {
+			return nil, err
+		}
+		data := make([]float32, int64(rows)*dim)
+		for i := range data {
+			data[i] = r.Float32()
+		}
+		fieldData.Field = &schemapb.FieldData_Vectors{
+			Vectors: &schemapb.VectorField{
+				Dim:  dim,
+				Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: data}},
+			},
+		}
+	default:
+		return nil, errors.Newf("random data of field %s with type %s is not supported", field.GetName(), field.GetDataType())
+	}
+	if field.GetNullable() {
+		fieldData.ValidData = lo.RepeatBy(rows, func(int) bool { return true })
+	}
+	return fieldData, nil
+}
+
 func NewInt64FieldData(fieldName string, numRows int) *schemapb.FieldData {
 	return &schemapb.FieldData{
 		Type:      schemapb.DataType_Int64,
@@ -0,0 +1,377 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// RaceLoadRelease issues LoadCollection and ReleaseCollection of the collection at the same time for rounds times.
+// Either request is allowed to fail since they conflict with each other,
+// but both of them must return within roundTimeout, otherwise the cluster is considered deadlocked.
+func (cluster *MiniClusterV2) RaceLoadRelease(ctx context.Context, dbName, collection string, rounds int, roundTimeout time.Duration) error {
+	for i := 0; i < rounds; i++ {
+		roundCtx, cancel := context.WithTimeout(ctx, roundTimeout)
+		var (
+			wg                  sync.WaitGroup
+			loadErr, releaseErr error
+		)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			status, err := cluster.Proxy.LoadCollection(roundCtx, &milvuspb.LoadCollectionRequest{
+				DbName:         dbName,
+				CollectionName: collection,
+			})
+			loadErr = merr.CheckRPCCall(status, err)
+		}()
+		go func() {
+			defer wg.Done()
+			status, err := cluster.Proxy.ReleaseCollection(roundCtx, &milvuspb.ReleaseCollectionRequest{
+				DbName:         dbName,
+				CollectionName: collection,
+			})
+			releaseErr = merr.CheckRPCCall(status, err)
+		}()
+		wg.Wait()
+		roundErr := roundCtx.Err()
+		cancel()
+		if roundErr != nil {
+			return errors.Wrapf(roundErr, "load and release of collection %s not returned in round %d", collection, i)
+		}
+		log.Info("load and release raced", zap.String("collection", collection), zap.Int("round", i),
+			zap.NamedError("loadErr", loadErr), zap.NamedError("releaseErr", releaseErr))
+	}
+	return nil
+}
+
+// VerifyLoadState waits until the collection is no longer loading, then checks the collection behaves as its load state claims:
+// a loaded collection shall be fully loaded and queryable, while querying a not loaded collection shall fail with ErrCollectionNotLoaded.
+// It returns the settled load state.
+func (cluster *MiniClusterV2) VerifyLoadState(ctx context.Context, dbName, collection string) (commonpb.LoadState, error) {
+	var state commonpb.LoadState
+	for {
+		resp, err := cluster.Proxy.GetLoadState(ctx, &milvuspb.GetLoadStateRequest{
+			DbName:         dbName,
+			CollectionName: collection,
+		})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return state, err
+		}
+		state = resp.GetState()
+		if state != commonpb.LoadState_LoadStateLoading {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return state, errors.Wrapf(ctx.Err(), "collection %s stuck in loading", collection)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	queryResult, err := cluster.Proxy.Query(ctx, &milvuspb.QueryRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+		OutputFields:   []string{"count(*)"},
+	})
+	queryErr := merr.CheckRPCCall(queryResult, err)
+
+	switch state {
+	case commonpb.LoadState_LoadStateLoaded:
+		progress, err := cluster.Proxy.GetLoadingProgress(ctx, &milvuspb.GetLoadingProgressRequest{
+			DbName:         dbName,
+			CollectionName: collection,
+		})
+		if err := merr.CheckRPCCall(progress, err); err != nil {
+			return state, err
+		}
+		if progress.GetProgress() != 100 {
+			return state, errors.Newf("collection %s is loaded but the loading progress is %d", collection, progress.GetProgress())
+		}
+		if queryErr != nil {
+			return state, errors.Wrapf(queryErr, "collection %s is loaded but not queryable", collection)
+		}
+	case commonpb.LoadState_LoadStateNotLoad:
+		if !errors.Is(queryErr, merr.ErrCollectionNotLoaded) {
+			return state, errors.Newf("query on not loaded collection %s shall fail with collection not loaded, got %v", collection, queryErr)
+		}
+	default:
+		return state, errors.Newf("unexpected load state %s of collection %s", state.String(), collection)
+	}
+	return state, nil
+}
+
+// LoadCollectionFields loads only the given fields of the collection and waits until the collection is fully loaded.
+func (cluster *MiniClusterV2) LoadCollectionFields(ctx context.Context, dbName, collection string, fields []string) error {
+	status, err := cluster.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+		LoadFields:     fields,
+	})
+	if err := merr.CheckRPCCall(status, err); err != nil {
+		return err
+	}
+	for {
+		progress, err := cluster.Proxy.GetLoadingProgress(ctx, &milvuspb.GetLoadingProgressRequest{
+			DbName:         dbName,
+			CollectionName: collection,
+		})
+		if err := merr.CheckRPCCall(progress, err); err != nil {
+			return err
+		}
+		if progress.GetProgress() == 100 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "collection %s not loaded, progress %d", collection, progress.GetProgress())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// VerifyLoadedFields checks that only the loaded fields of the collection are queryable:
+// querying a loaded field shall return it, while querying any other field shall either fail or leave it out of the result.
+func (cluster *MiniClusterV2) VerifyLoadedFields(ctx context.Context, dbName, collection string, loadedFields []string) error {
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return err
+	}
+
+	for _, field := range describeResp.GetSchema().GetFields() {
+		if field.GetIsDynamic() {
+			continue
+		}
+		queryResp, err := cluster.Proxy.Query(ctx, &milvuspb.QueryRequest{
+			DbName:         dbName,
+			CollectionName: collection,
+			OutputFields:   []string{field.GetName()},
+			QueryParams: []*commonpb.KeyValuePair{
+				{Key: LimitKey, Value: "1"},
+			},
+		})
+		queryErr := merr.CheckRPCCall(queryResp, err)
+		returned := queryErr == nil && lo.ContainsBy(queryResp.GetFieldsData(), func(fieldData *schemapb.FieldData) bool {
+			return fieldData.GetFieldName() == field.GetName()
+		})
+
+		if lo.Contains(loadedFields, field.GetName()) {
+			if queryErr != nil {
+				return errors.Wrapf(queryErr, "loaded field %s of collection %s is not queryable", field.GetName(), collection)
+			}
+			if !returned {
+				return errors.Newf("loaded field %s of collection %s is not returned", field.GetName(), collection)
+			}
+		} else if returned {
+			return errors.Newf("field %s of collection %s is returned but not loaded", field.GetName(), collection)
+		}
+	}
+	return nil
+}
+
+// RefreshLoad reloads the loaded collection to pick up the segments flushed after it's loaded,
+// and waits until the refresh is done.
+func (cluster *MiniClusterV2) RefreshLoad(ctx context.Context, collection string) error {
+	status, err := cluster.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		CollectionName: collection,
+		Refresh:        true,
+	})
+	if err := merr.CheckRPCCall(status, err); err != nil {
+		return err
+	}
+	for {
+		progress, err := cluster.Proxy.GetLoadingProgress(ctx, &milvuspb.GetLoadingProgressRequest{
+			CollectionName: collection,
+		})
+		if err := merr.CheckRPCCall(progress, err); err != nil {
+			return err
+		}
+		if progress.GetRefreshProgress() == 100 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "collection %s not refreshed, progress %d", collection, progress.GetRefreshProgress())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// RestartQueryCoordWithLoaded loads the collection in the default database, restarts querycoord,
+// then waits until the collection is recovered to loaded and the search succeeds, without loading it again.
+func (cluster *MiniClusterV2) RestartQueryCoordWithLoaded(ctx context.Context, collection string, searchReq *milvuspb.SearchRequest) error {
+	if err := cluster.LoadCollectionFields(ctx, "", collection, nil); err != nil {
+		return err
+	}
+	searchResult, err := cluster.Proxy.Search(ctx, searchReq)
+	if err := merr.CheckRPCCall(searchResult, err); err != nil {
+		return errors.Wrapf(err, "collection %s not searchable before querycoord restarts", collection)
+	}
+
+	cluster.StopQueryCoord()
+	cluster.StartQueryCoord()
+	log.Info("querycoord restarted", zap.String("collection", collection))
+
+	var lastErr error
+	for {
+		lastErr = cluster.checkLoadedAndSearchable(ctx, collection, searchReq)
+		if lastErr == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(lastErr, "collection %s not recovered after querycoord restarts", collection)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func (cluster *MiniClusterV2) checkLoadedAndSearchable(ctx context.Context, collection string, searchReq *milvuspb.SearchRequest) error {
+	resp, err := cluster.Proxy.GetLoadState(ctx, &milvuspb.GetLoadStateRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return err
+	}
+	if resp.GetState() != commonpb.LoadState_LoadStateLoaded {
+		return errors.Newf("collection %s is in load state %s", collection, resp.GetState().String())
+	}
+	searchResult, err := cluster.Proxy.Search(ctx, searchReq)
+	return merr.CheckRPCCall(searchResult, err)
+}
+
+// TrackLoadState polls the load state of the collection being loaded until it's loaded,
+// and checks the state stays Loading while the load is in progress:
+// neither NotLoad nor Loaded shall be reported before the loading progress reaches 100.
+// It returns whether the Loading state is observed, which is not the case if the load completes before the first poll.
+func (cluster *MiniClusterV2) TrackLoadState(ctx context.Context, dbName, collection string) (bool, error) {
+	var sawLoading bool
+	for {
+		// read the state before the progress, since the progress never goes backward
+		resp, err := cluster.Proxy.GetLoadState(ctx, &milvuspb.GetLoadStateRequest{
+			DbName:         dbName,
+			CollectionName: collection,
+		})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return sawLoading, err
+		}
+		progress, err := cluster.Proxy.GetLoadingProgress(ctx, &milvuspb.GetLoadingProgressRequest{
+			DbName:         dbName,
+			CollectionName: collection,
+		})
+		if err := merr.CheckRPCCall(progress, err); err != nil {
+			return sawLoading, err
+		}
+
+		switch resp.GetState() {
+		case commonpb.LoadState_LoadStateLoading:
+			sawLoading = true
+		case commonpb.LoadState_LoadStateLoaded:
+			if progress.GetProgress() != 100 {
+				return sawLoading, errors.Newf("collection %s is reported loaded but the loading progress is %d", collection, progress.GetProgress())
+			}
+			return sawLoading, nil
+		default:
+			return sawLoading, errors.Newf("collection %s being loaded is reported in load state %s, progress %d",
+				collection, resp.GetState().String(), progress.GetProgress())
+		}
+		select {
+		case <-ctx.Done():
+			return sawLoading, errors.Wrapf(ctx.Err(), "collection %s not loaded, progress %d", collection, progress.GetProgress())
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// loadReleaseGoroutineSlack is how many more goroutines than the baseline are tolerated after a release,
+// as the background tasks of the components come and go.
+const loadReleaseGoroutineSlack = 50
+
+// LoadReleaseCycle loads and releases the collection in the default database for times cycles,
+// and checks nothing is leaked by a cycle: the memory usage of the collection on querynodes shall be reported after each load
+// and drop back to zero after the release, and the goroutines of the cluster shall return to the baseline.
+// The baseline is taken after the first cycle, since the first load starts workers which are kept for later loads.
+func (cluster *MiniClusterV2) LoadReleaseCycle(ctx context.Context, collection string, times int) error {
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return err
+	}
+	collectionID := describeResp.GetCollectionID()
+
+	baseline := -1
+	for i := 0; i < times; i++ {
+		if err := cluster.LoadCollectionFields(ctx, "", collection, nil); err != nil {
+			return errors.Wrapf(err, "failed to load collection %s in cycle %d", collection, i)
+		}
+		// a collection missing from the metric reads as zero too, so make sure the loaded one is reported
+		usage, err := cluster.GetQueryNodeMemoryUsage(ctx)
+		if err != nil {
+			return err
+		}
+		if loaded, ok := usage[collectionID]; !ok || loaded <= 0 {
+			return errors.Newf("no memory usage of collection %s reported after load in cycle %d", collection, i)
+		}
+		status, err := cluster.Proxy.ReleaseCollection(ctx, &milvuspb.ReleaseCollectionRequest{
+			CollectionName: collection,
+		})
+		if err := merr.CheckRPCCall(status, err); err != nil {
+			return errors.Wrapf(err, "failed to release collection %s in cycle %d", collection, i)
+		}
+
+		for {
+			usage, err := cluster.GetQueryNodeMemoryUsage(ctx)
+			if err != nil {
+				return err
+			}
+			goroutines := runtime.NumGoroutine()
+			released := usage[collectionID] == 0
+			if released && baseline < 0 {
+				baseline = goroutines
+			}
+			if released && goroutines <= baseline+loadReleaseGoroutineSlack {
+				log.Info("collection load and release cycled", zap.String("collection", collection), zap.Int("cycle", i),
+					zap.Int("goroutines", goroutines), zap.Int("baseline", baseline))
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return errors.Wrapf(ctx.Err(), "cycle %d of collection %s leaks, memory usage %d, %d goroutines over baseline %d",
+					i, collection, usage[collectionID], goroutines, baseline)
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	mhttp "github.com/milvus-io/milvus/internal/http"
+	internalmetrics "github.com/milvus-io/milvus/internal/util/metrics"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/metrics"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/message"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// roleMetricsRegisters registers the metrics of each role into the registry, the same as cmd/roles does.
+var roleMetricsRegisters = map[string]func(registry *prometheus.Registry){
+	typeutil.RootCoordRole:     metrics.RegisterRootCoord,
+	typeutil.DataCoordRole:     metrics.RegisterDataCoord,
+	typeutil.QueryCoordRole:    metrics.RegisterQueryCoord,
+	typeutil.ProxyRole:         metrics.RegisterProxy,
+	typeutil.DataNodeRole:      metrics.RegisterDataNode,
+	typeutil.QueryNodeRole:     metrics.RegisterQueryNode,
+	typeutil.StreamingNodeRole: metrics.RegisterStreamingNode,
+}
+
+var (
+	managementOnce sync.Once
+	managementPort int
+	managementErr  error
+)
+
+// GetMetrics scrapes the metrics endpoint of role and returns the prometheus exposition text.
+// Components serve their metrics on the management http server of the process, at the metrics port they report,
+// which is brought up on the first scrape the same as a standalone milvus does, and served until the process exits.
+// All components live in the same process, so every role is served by the same endpoint,
+// the metrics of the nodes are told apart by the node_id label, and accumulate over the clusters run by the process.
+func (cluster *MiniClusterV2) GetMetrics(role string) (string, error) {
+	if !cluster.hasRole(role) {
+		return "", errors.Newf("no component of role %s in the cluster", role)
+	}
+	if err := startManagementServer(); err != nil {
+		return "", err
+	}
+	resp, err := http.Get(fmt.Sprintf("http://localhost:%d%s", managementPort, mhttp.MetricsPath))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Newf("fail to scrape metrics of %s, status: %s, body: %s", role, resp.Status, body)
+	}
+	return string(body), nil
+}
+
+// ParsedMetrics scrapes the metrics of role by GetMetrics, and returns the value of every series keyed by
+// the series in the exposition format, e.g. milvus_proxy_insert_vectors_count{collection_name="c",db_name="default",node_id="1"}.
+// Histograms and summaries are reported by their _count and _sum series.
+func (cluster *MiniClusterV2) ParsedMetrics(role string) (map[string]float64, error) {
+	text, err := cluster.GetMetrics(role)
+	if err != nil {
+		return nil, err
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(strings.NewReader(text))
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]float64)
+	for name, family := range families {
+		for _, m := range family.GetMetric() {
+			labels := seriesLabels(m.GetLabel())
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				ret[name+labels] = m.GetCounter().GetValue()
+			case dto.MetricType_GAUGE:
+				ret[name+labels] = m.GetGauge().GetValue()
+			case dto.MetricType_UNTYPED:
+				ret[name+labels] = m.GetUntyped().GetValue()
+			case dto.MetricType_HISTOGRAM:
+				ret[name+"_count"+labels] = float64(m.GetHistogram().GetSampleCount())
+				ret[name+"_sum"+labels] = m.GetHistogram().GetSampleSum()
+			case dto.MetricType_SUMMARY:
+				ret[name+"_count"+labels] = float64(m.GetSummary().GetSampleCount())
+				ret[name+"_sum"+labels] = m.GetSummary().GetSampleSum()
+			}
+		}
+	}
+	return ret, nil
+}
+
+func seriesLabels(pairs []*dto.LabelPair) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	labels := lo.Map(pairs, func(pair *dto.LabelPair, _ int) string {
+		return fmt.Sprintf("%s=%q", pair.GetName(), pair.GetValue())
+	})
+	sort.Strings(labels)
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+// hasRole tells whether the cluster runs any component of role.
+func (cluster *MiniClusterV2) hasRole(role string) bool {
+	cluster.mu.RLock()
+	defer cluster.mu.RUnlock()
+	switch role {
+	case typeutil.RootCoordRole:
+		return cluster.RootCoord != nil
+	case typeutil.DataCoordRole:
+		return cluster.DataCoord != nil
+	case typeutil.QueryCoordRole:
+		return cluster.QueryCoord != nil
+	case typeutil.ProxyRole:
+		return len(cluster.GetAllProxies()) > 0
+	case typeutil.DataNodeRole:
+		return len(cluster.GetAllDataNodes()) > 0
+	case typeutil.QueryNodeRole:
+		return len(cluster.GetAllQueryNodes()) > 0
+	case typeutil.StreamingNodeRole:
+		return len(cluster.GetAllStreamingNodes()) > 0
+	}
+	return false
+}
+
+// startManagementServer brings up the management http server of the process once, with the metrics of all roles
+// registered and served at /metrics as cmd/roles does. The server listens on a free port
+// passed by the METRICS_PORT environment, which is saved as the metrics port reported by the components.
+func startManagementServer() error {
+	managementOnce.Do(func() {
+		listener, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			managementErr = err
+			return
+		}
+		managementPort = listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+		port := strconv.Itoa(managementPort)
+
+		registry := internalmetrics.NewMilvusRegistry()
+		metrics.Register(registry.GoRegistry)
+		metrics.RegisterMetaMetrics(registry.GoRegistry)
+		metrics.RegisterMsgStreamMetrics(registry.GoRegistry)
+		metrics.RegisterStorageMetrics(registry.GoRegistry)
+		for _, register := range roleMetricsRegisters {
+			register(registry.GoRegistry)
+		}
+
+		os.Setenv(mhttp.ListenPortEnvKey, port)
+		params.Save(params.CommonCfg.MetricsPort.Key, port)
+		mhttp.ServeHTTP()
+		mhttp.Register(&mhttp.Handler{
+			Path:    mhttp.MetricsPath,
+			Handler: promhttp.HandlerFor(registry, promhttp.HandlerOpts{}),
+		})
+		managementErr = waitForPort(managementPort)
+		log.Info("management server started", zap.String("port", port), zap.Error(managementErr))
+	})
+	return managementErr
+}
+
+// waitForPort waits until the server listens on port.
+func waitForPort(port int) error {
+	addr := fmt.Sprintf("localhost:%d", port)
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			return conn.Close()
+		}
+		if time.Now().After(deadline) {
+			return errors.Wrapf(err, "management server not listening on %s", addr)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// GetQueryNodeMemoryUsage returns the memory size of the loaded segments, both growing and sealed,
+// summed over all healthy querynodes and keyed by collection id.
+// The entity size metric is refreshed while querynodes serve the system info metrics request,
+// and the refresh drops the values of other querynodes, so every node is refreshed and read in turn.
+func (cluster *MiniClusterV2) GetQueryNodeMemoryUsage(ctx context.Context) (map[int64]int64, error) {
+	usage := make(map[int64]int64)
+	for _, qn := range cluster.GetAllQueryNodes() {
+		state, err := qn.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+		if err != nil {
+			return nil, err
+		}
+		if state.GetState().GetStateCode() != commonpb.StateCode_Healthy {
+			continue
+		}
+
+		req, err := metricsinfo.ConstructRequestByMetricType(metricsinfo.SystemInfoMetrics)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := qn.GetQueryNode().GetMetrics(ctx, req)
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return nil, err
+		}
+
+		nodeID := fmt.Sprint(qn.GetQueryNode().GetNodeID())
+		for _, m := range collectMetrics(metrics.QueryNodeEntitiesSize) {
+			labels := make(map[string]string, len(m.GetLabel()))
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["node_id"] != nodeID {
+				continue
+			}
+			collectionID, err := strconv.ParseInt(labels["collection_id"], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			usage[collectionID] += int64(m.GetGauge().GetValue())
+		}
+	}
+	return usage, nil
+}
+
+// GetWALAppendCount returns the count of messages of msgType successfully appended to the wal,
+// summed over all pchannels of all streaming nodes. The count of a pchannel is dropped once its wal is closed.
+func (cluster *MiniClusterV2) GetWALAppendCount(msgType message.MessageType) int64 {
+	var count int64
+	for _, m := range collectMetrics(metrics.WALAppendMessageTotal) {
+		labels := make(map[string]string, len(m.GetLabel()))
+		for _, label := range m.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		if labels[metrics.WALMessageTypeLabelName] != msgType.String() || labels[metrics.StatusLabelName] != metrics.WALStatusOK {
+			continue
+		}
+		count += int64(m.GetCounter().GetValue())
+	}
+	return count
+}
+
+// GetSearchReduceLatency returns how many times search results have been reduced on querynodes and the total time spent,
+// reduces of both segment results and shard results are counted. Both accumulate since the process starts,
+// so the difference between two calls measures the reduces in between.
+func (cluster *MiniClusterV2) GetSearchReduceLatency() (uint64, time.Duration) {
+	var (
+		count uint64
+		sum   float64
+	)
+	for _, m := range collectMetrics(metrics.QueryNodeReduceLatency) {
+		isSearch := lo.ContainsBy(m.GetLabel(), func(label *dto.LabelPair) bool {
+			return label.GetName() == "query_type" && label.GetValue() == metrics.SearchLabel
+		})
+		if !isSearch {
+			continue
+		}
+		count += m.GetHistogram().GetSampleCount()
+		sum += m.GetHistogram().GetSampleSum()
+	}
+	// the latency is observed in milliseconds
+	return count, time.Duration(sum * float64(time.Millisecond))
+}
+
+func collectMetrics(collector prometheus.Collector) []*dto.Metric {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		collector.Collect(ch)
+		close(ch)
+	}()
+	ret := make([]*dto.Metric, 0)
+	for metric := range ch {
+		m := &dto.Metric{}
+		if err := metric.Write(m); err == nil {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,177 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"path"
+	"sync"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/coordinator/coordclient"
+	"github.com/milvus-io/milvus/internal/distributed/streaming"
+	"github.com/milvus-io/milvus/internal/streamingcoord/server/broadcaster/registry"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+)
+
+type stoppable interface {
+	Stop() error
+}
+
+// staleSession is the session of a crashed component, which is left in etcd until its lease expires.
+type staleSession struct {
+	key   string
+	value string
+	ttl   int64
+}
+
+// PowerLoss stops every component of the cluster at the same time without graceful stop,
+// as if the power of the whole cluster is cut, no component gets the chance to hand off its work to others.
+// Components run in-process and can only be stopped by Stop, which revokes their sessions,
+// so the sessions are put back afterwards under new leases of their remaining ttl,
+// that the sessions of the crashed components stay in etcd until they expire, as they do after a real crash.
+// The meta in etcd and the data in storage are kept, so that the cluster can be brought back by PowerOn.
+func (cluster *MiniClusterV2) PowerLoss() error {
+	ctx := cluster.ctx
+	sessions, err := cluster.listLiveSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	oldTimeout := params.CommonCfg.GracefulStopTimeout.GetValue()
+	params.Save(params.CommonCfg.GracefulStopTimeout.Key, "0")
+	defer params.Save(params.CommonCfg.GracefulStopTimeout.Key, oldTimeout)
+
+	var components []stoppable
+	if cluster.RootCoord != nil {
+		components = append(components, cluster.RootCoord)
+	}
+	if cluster.DataCoord != nil {
+		components = append(components, cluster.DataCoord)
+	}
+	if cluster.QueryCoord != nil {
+		components = append(components, cluster.QueryCoord)
+	}
+	if cluster.Proxy != nil {
+		components = append(components, cluster.Proxy)
+	}
+	for _, node := range cluster.proxies {
+		components = append(components, node)
+	}
+	if cluster.DataNode != nil {
+		components = append(components, cluster.DataNode)
+	}
+	for _, node := range cluster.datanodes {
+		components = append(components, node)
+	}
+	if cluster.QueryNode != nil {
+		components = append(components, cluster.QueryNode)
+	}
+	for _, node := range cluster.querynodes {
+		components = append(components, node)
+	}
+	for _, node := range cluster.GetAllStreamingNodes() {
+		components = append(components, node)
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Stop(); err != nil {
+				log.Warn("component failed to stop on power loss", zap.Error(err))
+			}
+		}()
+	}
+	wg.Wait()
+
+	cluster.RootCoord = nil
+	cluster.DataCoord = nil
+	cluster.QueryCoord = nil
+	cluster.Proxy = nil
+	cluster.proxies = nil
+	cluster.DataNode = nil
+	cluster.datanodes = nil
+	cluster.QueryNode = nil
+	cluster.querynodes = nil
+	cluster.StreamingNode = nil
+	cluster.streamingnodes = nil
+
+	for _, session := range sessions {
+		lease, err := cluster.EtcdCli.Grant(ctx, session.ttl)
+		if err != nil {
+			return err
+		}
+		if _, err := cluster.EtcdCli.Put(ctx, session.key, session.value, clientv3.WithLease(lease.ID)); err != nil {
+			return err
+		}
+	}
+	log.Info("mini cluster powered off", zap.Int("components", len(components)), zap.Int("staleSessions", len(sessions)))
+	return nil
+}
+
+// listLiveSessions returns the sessions of the cluster in etcd with the remaining ttl of their leases.
+func (cluster *MiniClusterV2) listLiveSessions(ctx context.Context) ([]staleSession, error) {
+	prefix := path.Join(params.EtcdCfg.MetaRootPath.GetValue(), sessionutil.DefaultServiceRoot) + "/"
+	resp, err := cluster.EtcdCli.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	var sessions []staleSession
+	for _, kv := range resp.Kvs {
+		// the id allocator of sessions is not bound to any lease
+		if kv.Lease == 0 {
+			continue
+		}
+		ttlResp, err := cluster.EtcdCli.TimeToLive(ctx, clientv3.LeaseID(kv.Lease))
+		if err != nil {
+			return nil, err
+		}
+		if ttlResp.TTL <= 0 {
+			continue
+		}
+		sessions = append(sessions, staleSession{key: string(kv.Key), value: string(kv.Value), ttl: ttlResp.TTL})
+	}
+	return sessions, nil
+}
+
+// PowerOn brings the cluster back after PowerLoss with new servers of all components,
+// which recover from the meta in etcd and the data in storage left by the previous ones.
+// The coordinators can't register until the sessions of their crashed predecessors expire.
+// Only the default nodes and the streaming nodes by WithStreamingNodes are brought back, not the extra ones added later.
+// The clients of the cluster are kept and reconnect to the new servers on the same ports.
+func (cluster *MiniClusterV2) PowerOn() error {
+	// the wal accesser of the previous servers is released here rather than on power loss,
+	// so that it's released exactly once even if the cluster stops without being powered on.
+	streaming.Release()
+	coordclient.ResetRegistration()
+	registry.ResetRegistration()
+	streaming.Init()
+
+	if err := cluster.newServers(cluster.ctx); err != nil {
+		return err
+	}
+	if err := cluster.startComponents(); err != nil {
+		return err
+	}
+	log.Info("mini cluster powered on")
+	return nil
+}
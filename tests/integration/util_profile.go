@@ -0,0 +1,68 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"os"
+	"path"
+	"runtime/pprof"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	CPUProfileFile  = "cpu.pprof"
+	HeapProfileFile = "heap.pprof"
+)
+
+func (cluster *MiniClusterV2) startProfiling() error {
+	if cluster.profileDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(cluster.profileDir, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.Create(path.Join(cluster.profileDir, CPUProfileFile))
+	if err != nil {
+		return err
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return errors.Wrap(err, "fail to start cpu profile")
+	}
+	cluster.cpuProfile = f
+	return nil
+}
+
+func (cluster *MiniClusterV2) stopProfiling() error {
+	if cluster.cpuProfile == nil {
+		return nil
+	}
+	pprof.StopCPUProfile()
+	err := cluster.cpuProfile.Close()
+	cluster.cpuProfile = nil
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path.Join(cluster.profileDir, HeapProfileFile))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return pprof.Lookup("heap").WriteTo(f, 0)
+}
@@ -25,6 +25,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
@@ -38,14 +40,17 @@ import (
 )
 
 const (
-	AnnsFieldKey    = "anns_field"
-	TopKKey         = "topk"
-	NQKey           = "nq"
-	MetricTypeKey   = common.MetricTypeKey
-	SearchParamsKey = common.IndexParamsKey
-	RoundDecimalKey = "round_decimal"
-	OffsetKey       = "offset"
-	LimitKey        = "limit"
+	AnnsFieldKey     = "anns_field"
+	TopKKey          = "topk"
+	NQKey            = "nq"
+	MetricTypeKey    = common.MetricTypeKey
+	SearchParamsKey  = common.IndexParamsKey
+	RoundDecimalKey  = "round_decimal"
+	OffsetKey        = "offset"
+	LimitKey         = "limit"
+	IgnoreGrowingKey = common.IgnoreGrowing
+	GroupByFieldKey  = "group_by_field"
+	GroupSizeKey     = "group_size"
 )
 
 func (s *MiniClusterSuite) WaitForLoadWithDB(ctx context.Context, dbName, collection string) {
@@ -109,6 +114,19 @@ func (s *MiniClusterSuite) waitForLoadInternal(ctx context.Context, dbName, coll
 	}
 }
 
+// LoadCollectionWithTiming loads the collection and waits until it's fully loaded,
+// it returns the time elapsed from the load request to the end of loading.
+func (s *MiniClusterSuite) LoadCollectionWithTiming(ctx context.Context, dbName, collection string) time.Duration {
+	start := time.Now()
+	loadStatus, err := s.Cluster.Proxy.LoadCollection(ctx, &milvuspb.LoadCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+	})
+	s.Require().NoError(merr.CheckRPCCall(loadStatus, err))
+	s.waitForLoadInternal(ctx, dbName, collection)
+	return time.Since(start)
+}
+
 func (s *MiniClusterSuite) WaitForLoadRefresh(ctx context.Context, dbName, collection string) {
 	cluster := s.Cluster
 	getLoadingProgress := func() *milvuspb.GetLoadingProgressResponse {
@@ -279,6 +297,121 @@ func ConstructSearchRequestWithConsistencyLevel(
 	}
 }
 
+// QueryCount returns the number of rows matching the expr in the collection of the default database by count(*),
+// an empty expr counts all rows. The query is strongly consistent.
+func (cluster *MiniClusterV2) QueryCount(ctx context.Context, collection, expr string) (int64, error) {
+	return cluster.QueryCountWithDB(ctx, "", collection, expr)
+}
+
+// QueryCountWithDB is QueryCount on the collection of the database.
+func (cluster *MiniClusterV2) QueryCountWithDB(ctx context.Context, dbName, collection, expr string) (int64, error) {
+	queryResp, err := cluster.Proxy.Query(ctx, &milvuspb.QueryRequest{
+		DbName:           dbName,
+		CollectionName:   collection,
+		Expr:             expr,
+		OutputFields:     []string{"count(*)"},
+		ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+	})
+	if err := merr.CheckRPCCall(queryResp, err); err != nil {
+		return 0, err
+	}
+	if len(queryResp.GetFieldsData()) != 1 || len(queryResp.GetFieldsData()[0].GetScalars().GetLongData().GetData()) != 1 {
+		return 0, errors.Newf("unexpected count(*) result: %v", queryResp.GetFieldsData())
+	}
+	return queryResp.GetFieldsData()[0].GetScalars().GetLongData().GetData()[0], nil
+}
+
+// WithIgnoreGrowing sets the ignore_growing param of the search request,
+// growing segments are excluded from the search if ignore is true.
+func WithIgnoreGrowing(req *milvuspb.SearchRequest, ignore bool) *milvuspb.SearchRequest {
+	req.SearchParams = append(req.SearchParams, &commonpb.KeyValuePair{
+		Key:   IgnoreGrowingKey,
+		Value: strconv.FormatBool(ignore),
+	})
+	return req
+}
+
+// WithSearchParams replaces the index specific params of the search request, such as ef of HNSW or nprobe of IVF.
+func WithSearchParams(req *milvuspb.SearchRequest, params map[string]any) *milvuspb.SearchRequest {
+	b, err := json.Marshal(params)
+	if err != nil {
+		panic(err)
+	}
+	for _, kv := range req.GetSearchParams() {
+		if kv.GetKey() == SearchParamsKey {
+			kv.Value = string(b)
+			return req
+		}
+	}
+	req.SearchParams = append(req.SearchParams, &commonpb.KeyValuePair{
+		Key:   SearchParamsKey,
+		Value: string(b),
+	})
+	return req
+}
+
+// WithSearchOffset sets the offset of the search request, the first offset hits of every query are skipped,
+// so that the results can be paged through with offset and topk.
+func WithSearchOffset(req *milvuspb.SearchRequest, offset int) *milvuspb.SearchRequest {
+	req.SearchParams = append(req.SearchParams, &commonpb.KeyValuePair{
+		Key:   OffsetKey,
+		Value: strconv.Itoa(offset),
+	})
+	return req
+}
+
+// CheckSearchPages checks the pages of a paged search, in the order of offset, are disjoint
+// and together are exactly the hits of the single search without offset, in the same order.
+func CheckSearchPages(full *schemapb.SearchResultData, pages ...*schemapb.SearchResultData) error {
+	pageOffsets := make([]int64, len(pages))
+	var fullOffset int64
+	for qi, topk := range full.GetTopks() {
+		var paged []any
+		for pi, page := range pages {
+			if len(page.GetTopks()) != len(full.GetTopks()) {
+				return errors.Newf("page %d has %d queries, expected %d", pi, len(page.GetTopks()), len(full.GetTopks()))
+			}
+			for i := pageOffsets[pi]; i < pageOffsets[pi]+page.GetTopks()[qi]; i++ {
+				paged = append(paged, typeutil.GetPK(page.GetIds(), i))
+			}
+			pageOffsets[pi] += page.GetTopks()[qi]
+		}
+		if duplicates := lo.FindDuplicates(paged); len(duplicates) > 0 {
+			return errors.Newf("pages of query %d overlap on %v", qi, duplicates)
+		}
+		if int64(len(paged)) != topk {
+			return errors.Newf("pages of query %d have %d hits in total, expected %d", qi, len(paged), topk)
+		}
+		for i, pk := range paged {
+			if expected := typeutil.GetPK(full.GetIds(), fullOffset+int64(i)); pk != expected {
+				return errors.Newf("hit %d of query %d in pages is %v, expected %v", i, qi, pk, expected)
+			}
+		}
+		fullOffset += topk
+	}
+	return nil
+}
+
+// CheckSearchTieBreak checks the hits with the same score of every query are ordered by pk ascending,
+// which is the order both segcore and proxy reduce tie-break by.
+func CheckSearchTieBreak(result *schemapb.SearchResultData) error {
+	var offset int64
+	for qi, topk := range result.GetTopks() {
+		for i := offset + 1; i < offset+topk; i++ {
+			if result.GetScores()[i] != result.GetScores()[i-1] {
+				continue
+			}
+			prev, cur := typeutil.GetPK(result.GetIds(), i-1), typeutil.GetPK(result.GetIds(), i)
+			if !typeutil.ComparePK(prev, cur) {
+				return errors.Newf("hits of query %d with the same score %f are not ordered by pk: %v before %v",
+					qi, result.GetScores()[i], prev, cur)
+			}
+		}
+		offset += topk
+	}
+	return nil
+}
+
 func constructPlaceholderGroup(nq, dim int, vectorType schemapb.DataType) *commonpb.PlaceholderGroup {
 	values := make([][]byte, 0, nq)
 	var placeholderType commonpb.PlaceholderType
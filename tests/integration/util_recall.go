@@ -0,0 +1,110 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// GroundTruthL2 returns the exact top k pks of every float vector query of the search request by brute force,
+// vectors are the row-major float vectors of the rows whose pks are given in the same order.
+func GroundTruthL2(req *milvuspb.SearchRequest, vectors []float32, pks []int64, dim, topk int) ([][]int64, error) {
+	if len(vectors) != len(pks)*dim {
+		return nil, errors.Newf("%d floats don't match %d rows of dim %d", len(vectors), len(pks), dim)
+	}
+	plg := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(req.GetPlaceholderGroup(), plg); err != nil {
+		return nil, err
+	}
+	if len(plg.GetPlaceholders()) != 1 || plg.GetPlaceholders()[0].GetType() != commonpb.PlaceholderType_FloatVector {
+		return nil, errors.New("only a single float vector placeholder is supported")
+	}
+
+	queries := plg.GetPlaceholders()[0].GetValues()
+	truth := make([][]int64, 0, len(queries))
+	for _, query := range queries {
+		if len(query) != dim*4 {
+			return nil, errors.Newf("query of %d bytes doesn't match dim %d", len(query), dim)
+		}
+		q := make([]float32, dim)
+		for j := range q {
+			q[j] = typeutil.BytesToFloat32(query[j*4 : (j+1)*4])
+		}
+		distances := make([]float32, len(pks))
+		for i := range pks {
+			var dist float32
+			for j, v := range vectors[i*dim : (i+1)*dim] {
+				diff := v - q[j]
+				dist += diff * diff
+			}
+			distances[i] = dist
+		}
+		order := make([]int, len(pks))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return distances[order[i]] < distances[order[j]]
+		})
+		k := min(topk, len(order))
+		ids := make([]int64, 0, k)
+		for _, idx := range order[:k] {
+			ids = append(ids, pks[idx])
+		}
+		truth = append(truth, ids)
+	}
+	return truth, nil
+}
+
+// Recall returns the fraction of the ground truth hit by the int64 pks of the search result, averaged over queries.
+func Recall(result *schemapb.SearchResultData, groundTruth [][]int64) float64 {
+	if len(groundTruth) == 0 {
+		return 0
+	}
+	ids := result.GetIds().GetIntId().GetData()
+	var (
+		offset int64
+		total  float64
+	)
+	for qi, truth := range groundTruth {
+		var topk int64
+		if qi < len(result.GetTopks()) {
+			topk = result.GetTopks()[qi]
+		}
+		hits := typeutil.NewSet(ids[offset : offset+topk]...)
+		offset += topk
+		if len(truth) == 0 {
+			continue
+		}
+		var hit int
+		for _, pk := range truth {
+			if hits.Contain(pk) {
+				hit++
+			}
+		}
+		total += float64(hit) / float64(len(truth))
+	}
+	return total / float64(len(groundTruth))
+}
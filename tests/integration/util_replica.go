@@ -0,0 +1,422 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	grpcquerynode "github.com/milvus-io/milvus/internal/distributed/querynode"
+	qctask "github.com/milvus-io/milvus/internal/querycoordv2/task"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/metrics"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// GetShardLeaders returns the shard leader node ids of every replica, keyed by channel name.
+func (cluster *MiniClusterV2) GetShardLeaders(ctx context.Context, collectionID int64) (map[string][]int64, error) {
+	resp, err := cluster.QueryCoordClient.GetShardLeaders(ctx, &querypb.GetShardLeadersRequest{
+		CollectionID: collectionID,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	leaders := make(map[string][]int64, len(resp.GetShards()))
+	for _, shard := range resp.GetShards() {
+		leaders[shard.GetChannelName()] = shard.GetNodeIds()
+	}
+	return leaders, nil
+}
+
+// GetQueryNodeDistribution returns the segments and channels served by each querynode, keyed by node id.
+// Querynodes which are not healthy, e.g. stopped ones, are left out, and so are those failing to report,
+// the returned error lists all of them while the distribution of the rest is still returned.
+func (cluster *MiniClusterV2) GetQueryNodeDistribution(ctx context.Context) (map[int64]*querypb.GetDataDistributionResponse, error) {
+	distribution := make(map[int64]*querypb.GetDataDistributionResponse)
+	var errs []error
+	for _, node := range cluster.GetAllQueryNodes() {
+		nodeID := node.GetQueryNode().GetNodeID()
+		states, err := node.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{})
+		if err := merr.CheckRPCCall(states, err); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to get state of querynode %d", nodeID))
+			continue
+		}
+		if code := states.GetState().GetStateCode(); code != commonpb.StateCode_Healthy {
+			errs = append(errs, errors.Newf("querynode %d is %s", nodeID, code.String()))
+			continue
+		}
+		resp, err := node.GetDataDistribution(ctx, &querypb.GetDataDistributionRequest{})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to get distribution of querynode %d", nodeID))
+			continue
+		}
+		distribution[nodeID] = resp
+	}
+	return distribution, merr.Combine(errs...)
+}
+
+// ScaleQueryNodes adds or removes extra querynodes until the cluster has target querynodes, the main one included,
+// which models the autoscaling of querynodes. Querynodes are added by AddQueryNode, and removed from the latest added,
+// so the main querynode is never removed.
+// On scale down, it blocks until the sealed segments and channels served by the removed querynodes are served by the survivors
+// as many times as before, i.e. querycoord has moved them off, so the collections shall not be compacted or released meanwhile.
+// On scale up, it only waits until the new querynodes are registered, balancing onto them is up to querycoord.
+func (cluster *MiniClusterV2) ScaleQueryNodes(ctx context.Context, target int) error {
+	if target < 1 {
+		return errors.Newf("invalid querynode number %d, the main querynode can't be removed", target)
+	}
+	current := 1 + len(cluster.querynodes)
+	if target >= current {
+		if _, err := cluster.AddQueryNodes(target - current); err != nil {
+			return err
+		}
+		return cluster.WaitForQueryNodeNum(ctx, target)
+	}
+
+	before, err := cluster.GetQueryNodeDistribution(ctx)
+	if err != nil {
+		return err
+	}
+	segmentReplicas, channelReplicas := countDistribution(before)
+	removed := slices.Clone(cluster.querynodes[target-1:])
+	moving := make(map[int64]*querypb.GetDataDistributionResponse, len(removed))
+	for _, node := range removed {
+		nodeID := node.GetQueryNode().GetNodeID()
+		moving[nodeID] = before[nodeID]
+	}
+	movingSegments, movingChannels := countDistribution(moving)
+	// the latest added is removed first
+	for i := len(removed) - 1; i >= 0; i-- {
+		if err := cluster.RemoveQueryNode(removed[i]); err != nil {
+			return err
+		}
+	}
+	if err := cluster.WaitForQueryNodeNum(ctx, target); err != nil {
+		return err
+	}
+
+	for {
+		var pendingSegments []int64
+		var pendingChannels []string
+		after, err := cluster.GetQueryNodeDistribution(ctx)
+		if err == nil {
+			segments, channels := countDistribution(after)
+			pendingSegments = lo.Filter(lo.Keys(movingSegments), func(id int64, _ int) bool {
+				return segments[id] < segmentReplicas[id]
+			})
+			pendingChannels = lo.Filter(lo.Keys(movingChannels), func(channel string, _ int) bool {
+				return channels[channel] < channelReplicas[channel]
+			})
+			if len(pendingSegments) == 0 && len(pendingChannels) == 0 {
+				log.Info("querynodes scaled down", zap.Int("target", target),
+					zap.Int("movedSegments", len(movingSegments)), zap.Int("movedChannels", len(movingChannels)))
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "segments %v and channels %v of the removed querynodes not moved to the survivors, last error: %v",
+				pendingSegments, pendingChannels, err)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// countDistribution counts how many querynodes serve each sealed segment and channel in the distribution.
+func countDistribution(distribution map[int64]*querypb.GetDataDistributionResponse) (map[int64]int, map[string]int) {
+	segments := make(map[int64]int)
+	channels := make(map[string]int)
+	for _, dist := range distribution {
+		for _, segment := range dist.GetSegments() {
+			segments[segment.GetID()]++
+		}
+		for _, channel := range dist.GetChannels() {
+			channels[channel.GetChannel()]++
+		}
+	}
+	return segments, channels
+}
+
+// ShardLeaderFailover stops the querynode which leads the first shard of the collection,
+// then waits until another querynode is elected as the new leader of that shard.
+// The stopped querynode is no longer tracked by the cluster, even if it's the main one.
+// It returns the node ids of the shard leader before and after the failover.
+func (cluster *MiniClusterV2) ShardLeaderFailover(ctx context.Context, collectionID int64) (int64, int64, error) {
+	leaders, err := cluster.GetShardLeaders(ctx, collectionID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(leaders) == 0 {
+		return 0, 0, errors.Newf("no shard leader found for collection %d", collectionID)
+	}
+	channels := lo.Keys(leaders)
+	sort.Strings(channels)
+	channel := channels[0]
+	before := leaders[channel]
+	oldLeader := before[0]
+
+	var leaderNode *grpcquerynode.Server
+	for _, node := range cluster.GetAllQueryNodes() {
+		if node.GetQueryNode().GetNodeID() == oldLeader {
+			leaderNode = node
+			break
+		}
+	}
+	if leaderNode == nil {
+		return 0, 0, errors.Newf("shard leader %d of channel %s is not tracked by the cluster", oldLeader, channel)
+	}
+	log.Info("stop shard leader", zap.String("channel", channel), zap.Int64("nodeID", oldLeader))
+	if err := leaderNode.Stop(); err != nil {
+		return 0, 0, err
+	}
+	cluster.untrackQueryNode(leaderNode)
+
+	for {
+		leaders, err := cluster.GetShardLeaders(ctx, collectionID)
+		if err == nil && !lo.Contains(leaders[channel], oldLeader) && len(leaders[channel]) >= len(before) {
+			newLeaders, _ := lo.Difference(leaders[channel], before)
+			if len(newLeaders) > 0 {
+				log.Info("new shard leader elected", zap.String("channel", channel), zap.Int64("nodeID", newLeaders[0]))
+				return oldLeader, newLeaders[0], nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return oldLeader, 0, errors.Wrapf(ctx.Err(), "no new shard leader elected for channel %s", channel)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// BalanceReport returns a human readable report of the collection in the default database for debugging balance issues,
+// including the nodes and shards of every replica, the shard leaders and the unfinished querycoord tasks of the collection.
+func (cluster *MiniClusterV2) BalanceReport(ctx context.Context, collection string) (string, error) {
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return "", err
+	}
+	collectionID := describeResp.GetCollectionID()
+
+	replicasResp, err := cluster.Proxy.GetReplicas(ctx, &milvuspb.GetReplicasRequest{
+		CollectionName: collection,
+		WithShardNodes: true,
+	})
+	if err := merr.CheckRPCCall(replicasResp, err); err != nil {
+		return "", err
+	}
+	leaders, err := cluster.GetShardLeaders(ctx, collectionID)
+	if err != nil {
+		return "", err
+	}
+	tasks, err := cluster.getQueryCoordTasks(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "collection %s (%d)\n", collection, collectionID)
+	for _, replica := range replicasResp.GetReplicas() {
+		fmt.Fprintf(&sb, "replica %d: resource group %s, nodes %v\n",
+			replica.GetReplicaID(), replica.GetResourceGroupName(), replica.GetNodeIds())
+		for _, shard := range replica.GetShardReplicas() {
+			fmt.Fprintf(&sb, "  shard %s: leader %d, nodes %v\n",
+				shard.GetDmChannelName(), shard.GetLeaderID(), shard.GetNodeIds())
+		}
+	}
+	channels := lo.Keys(leaders)
+	sort.Strings(channels)
+	for _, channel := range channels {
+		fmt.Fprintf(&sb, "shard leaders of %s: %v\n", channel, leaders[channel])
+	}
+	pending := lo.Filter(tasks, func(task *metricsinfo.QueryCoordTask, _ int) bool {
+		return task.CollectionID == collectionID && (task.TaskStatus == qctask.TaskStatusCreated || task.TaskStatus == qctask.TaskStatusStarted)
+	})
+	fmt.Fprintf(&sb, "pending tasks: %d\n", len(pending))
+	for _, task := range pending {
+		fmt.Fprintf(&sb, "  task %s: replica %d, status %s, priority %s, step %d, actions %v\n",
+			task.TaskName, task.Replica, task.TaskStatus, task.Priority, task.Step, task.Actions)
+	}
+	return sb.String(), nil
+}
+
+// getQueryCoordTasks returns the recent tasks of querycoord, finished tasks are kept for a while.
+func (cluster *MiniClusterV2) getQueryCoordTasks(ctx context.Context) ([]*metricsinfo.QueryCoordTask, error) {
+	req, err := metricsinfo.ConstructRequestByMetricType(metricsinfo.AllTaskKey)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cluster.QueryCoordClient.GetMetrics(ctx, req)
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	var tasks []*metricsinfo.QueryCoordTask
+	if resp.GetResponse() == "" {
+		return tasks, nil
+	}
+	if err := json.Unmarshal([]byte(resp.GetResponse()), &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// maxReplicaSearchAttempts bounds the searches issued by SearchAllReplicas to reach every replica.
+const maxReplicaSearchAttempts = 50
+
+// SearchAllReplicas searches the vector in the float vector field of the collection in the default database
+// until every replica has answered, it returns one result per replica ordered by replica id.
+// The search params and the metric type are the ones of the index on the field.
+// The replica serving a search is told by the leader search counter of querynodes,
+// so the collection must have a single shard and no other search shall run at the same time.
+// The proxy is expected to pick replicas in turn, see proxy.replicaSelectionPolicy.
+func (cluster *MiniClusterV2) SearchAllReplicas(ctx context.Context, collection string, vector []float32, topk int) ([]*milvuspb.SearchResults, error) {
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return nil, err
+	}
+	collectionID := describeResp.GetCollectionID()
+
+	replicasResp, err := cluster.Proxy.GetReplicas(ctx, &milvuspb.GetReplicasRequest{
+		CollectionID: collectionID,
+	})
+	if err := merr.CheckRPCCall(replicasResp, err); err != nil {
+		return nil, err
+	}
+	replicaOfLeader := make(map[string]int64)
+	for _, replica := range replicasResp.GetReplicas() {
+		if len(replica.GetShardReplicas()) != 1 {
+			return nil, errors.Newf("replica %d of collection %s has %d shards, only single shard is supported",
+				replica.GetReplicaID(), collection, len(replica.GetShardReplicas()))
+		}
+		replicaOfLeader[fmt.Sprint(replica.GetShardReplicas()[0].GetLeaderID())] = replica.GetReplicaID()
+	}
+
+	indexes, err := cluster.DescribeFieldIndexes(ctx, "", collection)
+	if err != nil {
+		return nil, err
+	}
+	index, ok := indexes[FloatVecField]
+	if !ok {
+		return nil, errors.Newf("no index on field %s of collection %s", FloatVecField, collection)
+	}
+	indexParams := funcutil.KeyValuePair2Map(index.GetParams())
+	indexType, metricType := indexParams[common.IndexTypeKey], indexParams[common.MetricTypeKey]
+	req := ConstructSearchRequest("", collection, "", FloatVecField, schemapb.DataType_FloatVector, nil,
+		metricType, GetSearchParams(indexType, metricType), 1, len(vector), topk, -1)
+	req.PlaceholderGroup, err = proto.Marshal(&commonpb.PlaceholderGroup{
+		Placeholders: []*commonpb.PlaceholderValue{
+			{
+				Tag:    "$0",
+				Type:   commonpb.PlaceholderType_FloatVector,
+				Values: [][]byte{typeutil.Float32ArrayToBytes(vector)},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[int64]*milvuspb.SearchResults, len(replicaOfLeader))
+	for i := 0; i < maxReplicaSearchAttempts && len(results) < len(replicaOfLeader); i++ {
+		before := leaderSearchCounts(collectionID)
+		result, err := cluster.Proxy.Search(ctx, req)
+		if err := merr.CheckRPCCall(result, err); err != nil {
+			return nil, err
+		}
+		after := leaderSearchCounts(collectionID)
+		served := lo.Filter(lo.Keys(after), func(node string, _ int) bool {
+			return after[node] > before[node]
+		})
+		if len(served) != 1 {
+			continue
+		}
+		if replicaID, ok := replicaOfLeader[served[0]]; ok {
+			if _, ok := results[replicaID]; !ok {
+				results[replicaID] = result
+			}
+		}
+	}
+	if len(results) < len(replicaOfLeader) {
+		return nil, errors.Newf("only %d of %d replicas of collection %s answered in %d searches",
+			len(results), len(replicaOfLeader), collection, maxReplicaSearchAttempts)
+	}
+
+	replicaIDs := lo.Keys(results)
+	sort.Slice(replicaIDs, func(i, j int) bool { return replicaIDs[i] < replicaIDs[j] })
+	return lo.Map(replicaIDs, func(replicaID int64, _ int) *milvuspb.SearchResults {
+		return results[replicaID]
+	}), nil
+}
+
+// leaderSearchCounts returns the count of searches handled by each shard leader for the collection, keyed by node id.
+func leaderSearchCounts(collectionID int64) map[string]float64 {
+	counts := make(map[string]float64)
+	for _, m := range collectMetrics(metrics.QueryNodeSQCount) {
+		labels := make(map[string]string, len(m.GetLabel()))
+		for _, label := range m.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		if labels["query_type"] != metrics.SearchLabel || labels["status"] != metrics.TotalLabel ||
+			labels["scope"] != metrics.Leader || labels["collection_id"] != fmt.Sprint(collectionID) {
+			continue
+		}
+		counts[labels["node_id"]] += m.GetCounter().GetValue()
+	}
+	return counts
+}
+
+// CompareSearchResults checks the search results hit the same ids with the same scores in the same order.
+func CompareSearchResults(expected, actual *milvuspb.SearchResults) error {
+	expectedIDs := expected.GetResults().GetIds().GetIntId().GetData()
+	actualIDs := actual.GetResults().GetIds().GetIntId().GetData()
+	if !slices.Equal(expectedIDs, actualIDs) {
+		return errors.Newf("search results hit different ids, expected: %v, actual: %v", expectedIDs, actualIDs)
+	}
+	expectedScores := expected.GetResults().GetScores()
+	actualScores := actual.GetResults().GetScores()
+	if len(expectedScores) != len(actualScores) {
+		return errors.Newf("search results have %d and %d scores", len(expectedScores), len(actualScores))
+	}
+	for i := range expectedScores {
+		if math.Abs(float64(expectedScores[i]-actualScores[i])) > 1e-6 {
+			return errors.Newf("score of id %d differs, expected: %f, actual: %f", expectedIDs[i], expectedScores[i], actualScores[i])
+		}
+	}
+	return nil
+}
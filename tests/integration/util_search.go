@@ -0,0 +1,486 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	grpcquerynode "github.com/milvus-io/milvus/internal/distributed/querynode"
+	"github.com/milvus-io/milvus/internal/registry"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metautil"
+	"github.com/milvus-io/milvus/pkg/v2/util/metric"
+)
+
+// cosineTolerance is the float error allowed for COSINE similarities out of [-1, 1].
+const cosineTolerance = 1e-5
+
+// SearchWithDebug runs the search and reports the loaded sealed segments holding each returned id, keyed by primary key.
+// Querynode doesn't trace the source segment of a hit, so the attribution is approximate:
+// it's resolved after the search from the primary key binlogs of the sealed segments loaded for the collection.
+// An id held by more than one sealed segment, e.g. inserted again with the same primary key, is attributed to all of them,
+// though only one of them served the hit. Ids served by growing segments are missing in the attribution.
+// Only int64 primary keys are supported.
+func (cluster *MiniClusterV2) SearchWithDebug(ctx context.Context, req *milvuspb.SearchRequest) (*milvuspb.SearchResults, map[int64][]int64, error) {
+	result, err := cluster.Proxy.Search(ctx, req)
+	if err := merr.CheckRPCCall(result, err); err != nil {
+		return nil, nil, err
+	}
+	ids := result.GetResults().GetIds().GetIntId().GetData()
+	if len(ids) == 0 {
+		return result, map[int64][]int64{}, nil
+	}
+
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         req.GetDbName(),
+		CollectionName: req.GetCollectionName(),
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return nil, nil, err
+	}
+	pkField, ok := lo.Find(describeResp.GetSchema().GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetIsPrimaryKey()
+	})
+	if !ok || pkField.GetDataType() != schemapb.DataType_Int64 {
+		return nil, nil, errors.Newf("collection %s has no int64 primary key", req.GetCollectionName())
+	}
+
+	segmentResp, err := cluster.Proxy.GetQuerySegmentInfo(ctx, &milvuspb.GetQuerySegmentInfoRequest{
+		DbName:         req.GetDbName(),
+		CollectionName: req.GetCollectionName(),
+	})
+	if err := merr.CheckRPCCall(segmentResp, err); err != nil {
+		return nil, nil, err
+	}
+	loaded := lo.SliceToMap(lo.Filter(segmentResp.GetInfos(), func(info *milvuspb.QuerySegmentInfo, _ int) bool {
+		return info.GetState() != commonpb.SegmentState_Growing
+	}), func(info *milvuspb.QuerySegmentInfo) (int64, struct{}) {
+		return info.GetSegmentID(), struct{}{}
+	})
+
+	segments, err := cluster.MetaWatcher.ShowSegments()
+	if err != nil {
+		return nil, nil, err
+	}
+	wanted := lo.SliceToMap(ids, func(id int64) (int64, struct{}) {
+		return id, struct{}{}
+	})
+	attribution := make(map[int64][]int64, len(ids))
+	for _, segment := range segments {
+		if _, ok := loaded[segment.GetID()]; !ok {
+			continue
+		}
+		for _, fieldBinlog := range segment.GetBinlogs() {
+			if fieldBinlog.GetFieldID() != pkField.GetFieldID() {
+				continue
+			}
+			for _, binlog := range fieldBinlog.GetBinlogs() {
+				logPath := binlog.GetLogPath()
+				if logPath == "" {
+					logPath = metautil.BuildInsertLogPath(cluster.ChunkManager.RootPath(), segment.GetCollectionID(),
+						segment.GetPartitionID(), segment.GetID(), fieldBinlog.GetFieldID(), binlog.GetLogID())
+				}
+				pks, err := cluster.readInt64Binlog(ctx, logPath)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "failed to read binlog %s of segment %d", logPath, segment.GetID())
+				}
+				for _, pk := range pks {
+					if _, ok := wanted[pk]; ok && !slices.Contains(attribution[pk], segment.GetID()) {
+						attribution[pk] = append(attribution[pk], segment.GetID())
+					}
+				}
+			}
+		}
+	}
+	return result, attribution, nil
+}
+
+// CheckFilteredSearch runs the search with the filter expr of the request on the collection in the default database,
+// and checks the filter is applied before or during the vector search rather than on its results:
+// every hit shall match the filter, and every query shall still return min(topk, matching rows) hits.
+func (cluster *MiniClusterV2) CheckFilteredSearch(ctx context.Context, req *milvuspb.SearchRequest) (*milvuspb.SearchResults, error) {
+	if req.GetDsl() == "" {
+		return nil, errors.New("search request has no filter")
+	}
+	topk, err := searchTopK(req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := cluster.Proxy.Search(ctx, req)
+	if err := merr.CheckRPCCall(result, err); err != nil {
+		return nil, err
+	}
+	matching, err := cluster.QueryCount(ctx, req.GetCollectionName(), req.GetDsl())
+	if err != nil {
+		return nil, err
+	}
+	for qi, hits := range result.GetResults().GetTopks() {
+		if expected := min(topk, matching); hits != expected {
+			return nil, errors.Newf("query %d returns %d hits with filter %q, expected %d of %d matching rows",
+				qi, hits, req.GetDsl(), expected, matching)
+		}
+	}
+
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: req.GetCollectionName(),
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return nil, err
+	}
+	pkField, ok := lo.Find(describeResp.GetSchema().GetFields(), func(field *schemapb.FieldSchema) bool {
+		return field.GetIsPrimaryKey()
+	})
+	if !ok {
+		return nil, errors.Newf("collection %s has no primary key", req.GetCollectionName())
+	}
+	var pks []string
+	switch ids := result.GetResults().GetIds(); pkField.GetDataType() {
+	case schemapb.DataType_Int64:
+		pks = lo.Map(lo.Uniq(ids.GetIntId().GetData()), func(pk int64, _ int) string {
+			return strconv.FormatInt(pk, 10)
+		})
+	case schemapb.DataType_VarChar:
+		pks = lo.Map(lo.Uniq(ids.GetStrId().GetData()), func(pk string, _ int) string {
+			return strconv.Quote(pk)
+		})
+	default:
+		return nil, errors.Newf("unsupported primary key type %s", pkField.GetDataType().String())
+	}
+	if len(pks) == 0 {
+		return result, nil
+	}
+	matched, err := cluster.QueryCount(ctx, req.GetCollectionName(),
+		fmt.Sprintf("(%s) && %s in [%s]", req.GetDsl(), pkField.GetName(), strings.Join(pks, ",")))
+	if err != nil {
+		return nil, err
+	}
+	if matched != int64(len(pks)) {
+		return nil, errors.Newf("%d of %d hits don't match filter %q", int64(len(pks))-matched, len(pks), req.GetDsl())
+	}
+	return result, nil
+}
+
+// CheckBatchedSearch runs the search of more than one query vectors, and checks every query of the batch
+// gets the same int64 pks as it does being searched alone, so that the results of queries are neither mixed up nor shifted.
+func (cluster *MiniClusterV2) CheckBatchedSearch(ctx context.Context, req *milvuspb.SearchRequest) (*milvuspb.SearchResults, error) {
+	plg := &commonpb.PlaceholderGroup{}
+	if err := proto.Unmarshal(req.GetPlaceholderGroup(), plg); err != nil {
+		return nil, err
+	}
+	if len(plg.GetPlaceholders()) != 1 {
+		return nil, errors.New("only a single placeholder is supported")
+	}
+	placeholder := plg.GetPlaceholders()[0]
+	queries := placeholder.GetValues()
+	if len(queries) < 2 {
+		return nil, errors.Newf("search request has %d queries, not batched", len(queries))
+	}
+
+	result, err := cluster.Proxy.Search(ctx, req)
+	if err := merr.CheckRPCCall(result, err); err != nil {
+		return nil, err
+	}
+	topks := result.GetResults().GetTopks()
+	if len(topks) != len(queries) {
+		return nil, errors.Newf("search of %d queries returns results of %d queries", len(queries), len(topks))
+	}
+	ids := result.GetResults().GetIds().GetIntId().GetData()
+	var offset int64
+	for qi, query := range queries {
+		single := proto.Clone(req).(*milvuspb.SearchRequest)
+		single.Nq = 1
+		single.PlaceholderGroup, err = proto.Marshal(&commonpb.PlaceholderGroup{
+			Placeholders: []*commonpb.PlaceholderValue{
+				{
+					Tag:    placeholder.GetTag(),
+					Type:   placeholder.GetType(),
+					Values: [][]byte{query},
+				},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		singleResult, err := cluster.Proxy.Search(ctx, single)
+		if err := merr.CheckRPCCall(singleResult, err); err != nil {
+			return nil, err
+		}
+		expected := singleResult.GetResults().GetIds().GetIntId().GetData()
+		actual := ids[offset : offset+topks[qi]]
+		if !slices.Equal(expected, actual) {
+			return nil, errors.Newf("query %d hits %v in the batch, but %v alone", qi, actual, expected)
+		}
+		offset += topks[qi]
+	}
+	return result, nil
+}
+
+// SearchGroupBySize runs the search grouped by the scalar field groupField with at most groupSize hits per group,
+// and checks every query returns at most topk groups, each of which has at most groupSize hits sharing the same group value.
+func (cluster *MiniClusterV2) SearchGroupBySize(ctx context.Context, req *milvuspb.SearchRequest, groupField string, groupSize int) (*milvuspb.SearchResults, error) {
+	topk, err := searchTopK(req)
+	if err != nil {
+		return nil, err
+	}
+	req.SearchParams = append(req.SearchParams,
+		&commonpb.KeyValuePair{Key: GroupByFieldKey, Value: groupField},
+		&commonpb.KeyValuePair{Key: GroupSizeKey, Value: strconv.Itoa(groupSize)},
+	)
+	result, err := cluster.Proxy.Search(ctx, req)
+	if err := merr.CheckRPCCall(result, err); err != nil {
+		return nil, err
+	}
+
+	groupValues := result.GetResults().GetGroupByFieldValue()
+	if groupValues.GetFieldName() != groupField {
+		return nil, errors.Newf("search is grouped by field %q, expected %q", groupValues.GetFieldName(), groupField)
+	}
+	var offset int64
+	for qi, hits := range result.GetResults().GetTopks() {
+		groups := make(map[string]int)
+		for i := offset; i < offset+hits; i++ {
+			value, err := groupValueAt(groupValues, i)
+			if err != nil {
+				return nil, err
+			}
+			groups[value]++
+			if groups[value] > groupSize {
+				return nil, errors.Newf("group %s of query %d has more than %d hits", value, qi, groupSize)
+			}
+		}
+		if int64(len(groups)) > topk {
+			return nil, errors.Newf("query %d returns %d groups, more than topk %d", qi, len(groups), topk)
+		}
+		offset += hits
+	}
+	return result, nil
+}
+
+// faultyQueryNode fails every Search request sent to the querynode while err is set,
+// other requests such as SearchSegments are passed to the querynode.
+type faultyQueryNode struct {
+	types.QueryNode
+	err atomic.Error
+}
+
+func (qn *faultyQueryNode) Search(ctx context.Context, req *querypb.SearchRequest) (*internalpb.SearchResults, error) {
+	if err := qn.err.Load(); err != nil {
+		return &internalpb.SearchResults{Status: merr.Status(err)}, nil
+	}
+	return qn.QueryNode.Search(ctx, req)
+}
+
+// InjectSearchFailure makes the querynode fail the Search requests with err, as if its index is broken,
+// a nil err brings the querynode back to normal and registers it into the resolver again, and so does Stop.
+// The querynode is replaced in the in-memory resolver shared by the whole process, so every client resolving it
+// afterwards gets the faulty one, the delegators on other querynodes included, not only proxy.
+// Only Search fails, which proxy sends to the shard delegators, while the SearchSegments sent by delegators
+// to their workers still succeed, so the failure surfaces when the querynode serves as a delegator.
+// Clients of querynodes are cached, so the first injection on a querynode shall happen
+// before it's searched, i.e. before the collection is loaded.
+func (cluster *MiniClusterV2) InjectSearchFailure(server *grpcquerynode.Server, err error) {
+	cluster.ptmu.Lock()
+	defer cluster.ptmu.Unlock()
+	node := server.GetQueryNode()
+	if cluster.searchFaults == nil {
+		cluster.searchFaults = make(map[int64]*faultyQueryNode)
+	}
+	faulty, ok := cluster.searchFaults[node.GetNodeID()]
+	if err == nil {
+		if ok {
+			// clients cached already keep the faulty one, which passes every request through from now on
+			faulty.err.Store(nil)
+			registry.GetInMemoryResolver().RegisterQueryNode(node.GetNodeID(), faulty.QueryNode)
+			delete(cluster.searchFaults, node.GetNodeID())
+		}
+		return
+	}
+	if !ok {
+		faulty = &faultyQueryNode{QueryNode: node}
+		cluster.searchFaults[node.GetNodeID()] = faulty
+		registry.GetInMemoryResolver().RegisterQueryNode(node.GetNodeID(), faulty)
+	}
+	faulty.err.Store(err)
+}
+
+// clearSearchFailures registers the querynodes replaced by InjectSearchFailure back into the in-memory resolver.
+func (cluster *MiniClusterV2) clearSearchFailures() {
+	cluster.ptmu.Lock()
+	defer cluster.ptmu.Unlock()
+	for nodeID, faulty := range cluster.searchFaults {
+		faulty.err.Store(nil)
+		registry.GetInMemoryResolver().RegisterQueryNode(nodeID, faulty.QueryNode)
+	}
+	cluster.searchFaults = nil
+}
+
+// CheckSearchFailure runs the search expected to fail with the failure injected by InjectSearchFailure,
+// and checks proxy surfaces the error of querynode within d rather than timing out or hiding it behind another error.
+func (cluster *MiniClusterV2) CheckSearchFailure(ctx context.Context, req *milvuspb.SearchRequest, expected error, d time.Duration) error {
+	searchCtx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	start := time.Now()
+	result, err := cluster.Proxy.Search(searchCtx, req)
+	err = merr.CheckRPCCall(result, err)
+	if err == nil {
+		return errors.New("search succeeds with the failure injected")
+	}
+	if searchCtx.Err() != nil {
+		return errors.Wrapf(err, "search failure not surfaced in %s", d)
+	}
+	if !strings.Contains(err.Error(), expected.Error()) {
+		return errors.Newf("search fails with %q, expected the error of querynode %q", err.Error(), expected.Error())
+	}
+	log.Info("search failure surfaced", zap.Duration("elapsed", time.Since(start)), zap.Error(err))
+	return nil
+}
+
+// CheckOversizedTopK runs the search with its topk raised just above quotaAndLimits.limits.topK,
+// and checks it's rejected with an error naming the topk and the limit, rather than clamped to the limit.
+// The search as requested shall still succeed afterwards.
+func (cluster *MiniClusterV2) CheckOversizedTopK(ctx context.Context, req *milvuspb.SearchRequest) error {
+	limit := params.QuotaConfig.TopKLimit.GetAsInt64()
+	oversized := proto.Clone(req).(*milvuspb.SearchRequest)
+	topk := strconv.FormatInt(limit+1, 10)
+	oversized.SearchParams = lo.Map(req.GetSearchParams(), func(kv *commonpb.KeyValuePair, _ int) *commonpb.KeyValuePair {
+		if kv.GetKey() == common.TopKKey {
+			return &commonpb.KeyValuePair{Key: common.TopKKey, Value: topk}
+		}
+		return kv
+	})
+	result, err := cluster.Proxy.Search(ctx, oversized)
+	err = merr.CheckRPCCall(result, err)
+	if err == nil {
+		return errors.Newf("search with topk %s beyond limit %d succeeds with %d hits", topk, limit, len(result.GetResults().GetScores()))
+	}
+	if !strings.Contains(err.Error(), topk) || !strings.Contains(err.Error(), strconv.FormatInt(limit, 10)) {
+		return errors.Newf("search with topk %s beyond limit %d fails with %q, which doesn't tell the topk and the limit", topk, limit, err.Error())
+	}
+	log.Info("oversized topk rejected", zap.String("topk", topk), zap.Error(err))
+
+	result, err = cluster.Proxy.Search(ctx, req)
+	return merr.CheckRPCCall(result, err)
+}
+
+// CheckScoreOrdering checks the scores of every query of the search result are ordered and signed by the metric:
+// L2 distances are non-negative and ascending, while IP and COSINE similarities are descending,
+// with COSINE ones within [-1, 1].
+func CheckScoreOrdering(results *schemapb.SearchResultData, metricType string) error {
+	scores := results.GetScores()
+	var total int64
+	for _, topk := range results.GetTopks() {
+		total += topk
+	}
+	if int64(len(scores)) != total {
+		return errors.Newf("%d scores returned for %d hits", len(scores), total)
+	}
+	descending := metric.PositivelyRelated(metricType)
+	var offset int64
+	for qi, topk := range results.GetTopks() {
+		hits := scores[offset : offset+topk]
+		for i, score := range hits {
+			switch {
+			case strings.EqualFold(metricType, metric.L2) && score < 0:
+				return errors.Newf("negative L2 distance %f of hit %d of query %d", score, i, qi)
+			case strings.EqualFold(metricType, metric.COSINE) && (score < -1-cosineTolerance || score > 1+cosineTolerance):
+				return errors.Newf("COSINE similarity %f of hit %d of query %d out of [-1, 1]", score, i, qi)
+			}
+			if i == 0 {
+				continue
+			}
+			if (descending && score > hits[i-1]) || (!descending && score < hits[i-1]) {
+				return errors.Newf("scores of query %d are not ordered by %s, hit %d has score %f after %f", qi, metricType, i, score, hits[i-1])
+			}
+		}
+		offset += topk
+	}
+	return nil
+}
+
+func groupValueAt(fieldData *schemapb.FieldData, i int64) (string, error) {
+	scalars := fieldData.GetScalars()
+	switch fieldData.GetType() {
+	case schemapb.DataType_Bool:
+		return strconv.FormatBool(scalars.GetBoolData().GetData()[i]), nil
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		return strconv.FormatInt(int64(scalars.GetIntData().GetData()[i]), 10), nil
+	case schemapb.DataType_Int64:
+		return strconv.FormatInt(scalars.GetLongData().GetData()[i], 10), nil
+	case schemapb.DataType_VarChar:
+		return scalars.GetStringData().GetData()[i], nil
+	default:
+		return "", errors.Newf("unsupported group by field type %s", fieldData.GetType().String())
+	}
+}
+
+func searchTopK(req *milvuspb.SearchRequest) (int64, error) {
+	topk, err := strconv.ParseInt(funcutil.KeyValuePair2Map(req.GetSearchParams())[common.TopKKey], 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "search request has no valid topk")
+	}
+	return topk, nil
+}
+
+func (cluster *MiniClusterV2) readInt64Binlog(ctx context.Context, logPath string) ([]int64, error) {
+	data, err := cluster.ChunkManager.Read(ctx, logPath)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := storage.NewBinlogReader(data)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var values []int64
+	for {
+		eventReader, err := reader.NextEventReader()
+		if err != nil {
+			return nil, err
+		}
+		if eventReader == nil {
+			return values, nil
+		}
+		payload, _, err := eventReader.GetInt64FromPayload()
+		eventReader.Close()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, payload...)
+	}
+}
@@ -0,0 +1,135 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// CheckRowCountInvariant checks that the rows of persistent segments minus the persisted deletes
+// equal the queryable row count of the collection.
+// The collection must be loaded and all its data flushed before calling it.
+// Note that a delete dispatched to several segments by L0 compaction is counted once per segment,
+// so the check is exact before L0 compaction runs or after the deletes are compacted away.
+func (cluster *MiniClusterV2) CheckRowCountInvariant(ctx context.Context, collection string) error {
+	infoResp, err := cluster.Proxy.GetPersistentSegmentInfo(ctx, &milvuspb.GetPersistentSegmentInfoRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(infoResp, err); err != nil {
+		return err
+	}
+
+	segments, err := cluster.MetaWatcher.ShowSegments()
+	if err != nil {
+		return err
+	}
+	deltaEntries := make(map[int64]int64)
+	for _, segment := range segments {
+		for _, fieldBinlog := range segment.GetDeltalogs() {
+			for _, binlog := range fieldBinlog.GetBinlogs() {
+				deltaEntries[segment.GetID()] += binlog.GetEntriesNum()
+			}
+		}
+	}
+
+	var inserted, deleted int64
+	for _, info := range infoResp.GetInfos() {
+		if info.GetLevel() != commonpb.SegmentLevel_L0 {
+			inserted += info.GetNumRows()
+		}
+		deleted += deltaEntries[info.GetSegmentID()]
+	}
+
+	count, err := cluster.QueryCount(ctx, collection, "")
+	if err != nil {
+		return err
+	}
+
+	if inserted-deleted != count {
+		return errors.Newf("row count invariant broken, collection: %s, inserted: %d, deleted: %d, queryable: %d",
+			collection, inserted, deleted, count)
+	}
+	return nil
+}
+
+// GetQuerySegmentIndexUsage returns whether each loaded segment of the collection in the default database is served from index,
+// keyed by segment id. Only sealed segments are reported by GetQuerySegmentInfo,
+// so a growing segment, which is always served from raw data, has no entry in the result.
+func (cluster *MiniClusterV2) GetQuerySegmentIndexUsage(ctx context.Context, collection string) (map[int64]bool, error) {
+	resp, err := cluster.Proxy.GetQuerySegmentInfo(ctx, &milvuspb.GetQuerySegmentInfoRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	usage := make(map[int64]bool, len(resp.GetInfos()))
+	for _, info := range resp.GetInfos() {
+		usage[info.GetSegmentID()] = info.GetState() != commonpb.SegmentState_Growing && info.GetIndexID() != 0
+	}
+	return usage, nil
+}
+
+// WaitForSegmentsSealed waits until the collection has segments and none of them is growing any more,
+// it returns the segments of the collection.
+func (cluster *MiniClusterV2) WaitForSegmentsSealed(ctx context.Context, collectionID int64) ([]*datapb.SegmentInfo, error) {
+	for {
+		segments, err := cluster.MetaWatcher.ShowSegments()
+		if err != nil {
+			return nil, err
+		}
+		segments = lo.Filter(segments, func(segment *datapb.SegmentInfo, _ int) bool {
+			return segment.GetCollectionID() == collectionID
+		})
+		growing := lo.CountBy(segments, func(segment *datapb.SegmentInfo) bool {
+			return segment.GetState() == commonpb.SegmentState_Growing
+		})
+		if len(segments) > 0 && growing == 0 {
+			return segments, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrapf(ctx.Err(), "segments of collection %d not sealed, growing: %d", collectionID, growing)
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// CheckSegmentsLoaded checks the sealed segments are all loaded for the collection in the default database.
+func (cluster *MiniClusterV2) CheckSegmentsLoaded(ctx context.Context, collection string, segmentIDs []int64) error {
+	resp, err := cluster.Proxy.GetQuerySegmentInfo(ctx, &milvuspb.GetQuerySegmentInfoRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return err
+	}
+	loaded := lo.Map(resp.GetInfos(), func(info *milvuspb.QuerySegmentInfo, _ int) int64 {
+		return info.GetSegmentID()
+	})
+	if missing, _ := lo.Difference(segmentIDs, loaded); len(missing) > 0 {
+		return errors.Newf("segments %v of collection %s are not loaded", missing, collection)
+	}
+	return nil
+}
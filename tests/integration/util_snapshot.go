@@ -0,0 +1,60 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/rootcoordpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// TriggerMetaSnapshot takes a snapshot of the meta of rootcoord and returns the timestamp of it.
+// Rootcoord keeps every version of its meta in etcd suffixed by the timestamp it's saved at,
+// so a snapshot is simply a timestamp allocated from rootcoord, at which all meta saved before it can be read back
+// until it expires after metastore.snapshot.ttl.
+func (cluster *MiniClusterV2) TriggerMetaSnapshot(ctx context.Context) (uint64, error) {
+	resp, err := cluster.RootCoordClient.AllocTimestamp(ctx, &rootcoordpb.AllocTimestampRequest{
+		Count: 1,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return 0, err
+	}
+	return resp.GetTimestamp(), nil
+}
+
+// CheckMetaSnapshot checks the collections of the database at the snapshot taken by TriggerMetaSnapshot
+// are exactly the expected ones, no matter what has been changed since then, a zero ts checks the latest meta.
+// After rootcoord restarts, it checks the snapshot is recovered from etcd as well.
+func (cluster *MiniClusterV2) CheckMetaSnapshot(ctx context.Context, dbName string, ts uint64, collections []string) error {
+	resp, err := cluster.RootCoordClient.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{
+		DbName:    dbName,
+		TimeStamp: ts,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return err
+	}
+	missing, unexpected := lo.Difference(collections, resp.GetCollectionNames())
+	if len(missing) > 0 || len(unexpected) > 0 {
+		return errors.Newf("collections at snapshot %d mismatch, missing: %v, unexpected: %v", ts, missing, unexpected)
+	}
+	return nil
+}
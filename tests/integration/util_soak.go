@@ -0,0 +1,100 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// soakHealthCheckInterval is how often Soak asserts the cluster is healthy while the workload runs.
+const soakHealthCheckInterval = 5 * time.Second
+
+// Soak runs the workload over and over for the duration, while asserting the cluster stays healthy by CheckHealth
+// every soakHealthCheckInterval and once more in the end. It returns on the first failure of either,
+// a running workload is not interrupted but no more iterations are started.
+func (cluster *MiniClusterV2) Soak(ctx context.Context, duration time.Duration, workload func(*MiniClusterV2) error) error {
+	soakCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var unhealthy atomic.Error
+	checkerDone := make(chan struct{})
+	go func() {
+		defer close(checkerDone)
+		ticker := time.NewTicker(soakHealthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-soakCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := cluster.checkHealth(soakCtx); err != nil && soakCtx.Err() == nil {
+				unhealthy.Store(err)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	start := time.Now()
+	iterations := 0
+	var workloadErr error
+	for soakCtx.Err() == nil {
+		if workloadErr = workload(cluster); workloadErr != nil {
+			break
+		}
+		iterations++
+	}
+	cancel()
+	<-checkerDone
+
+	if workloadErr != nil {
+		return errors.Wrapf(workloadErr, "soak workload fails in iteration %d after %s", iterations, time.Since(start))
+	}
+	if err := unhealthy.Load(); err != nil {
+		return errors.Wrapf(err, "cluster turns unhealthy after %d soak iterations in %s", iterations, time.Since(start))
+	}
+	if ctx.Err() != nil {
+		return errors.Wrapf(ctx.Err(), "soak stopped after %d iterations in %s", iterations, time.Since(start))
+	}
+	if err := cluster.checkHealth(ctx); err != nil {
+		return errors.Wrapf(err, "cluster unhealthy after %d soak iterations", iterations)
+	}
+	log.Info("soak succeed", zap.Int("iterations", iterations), zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
+// checkHealth fails if proxy doesn't report the cluster healthy.
+func (cluster *MiniClusterV2) checkHealth(ctx context.Context) error {
+	resp, err := cluster.Proxy.CheckHealth(ctx, &milvuspb.CheckHealthRequest{})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return err
+	}
+	if !resp.GetIsHealthy() {
+		return errors.Newf("cluster is not healthy, reasons: %v", resp.GetReasons())
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/dependency"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metautil"
+)
+
+// ErrStorageReadOnly is returned by the chunk managers of the cluster for every mutation while the storage is read-only.
+var ErrStorageReadOnly = errors.New("storage is read-only")
+
+// storageFaults holds the faults injected into the chunk managers of the cluster.
+type storageFaults struct {
+	enabled  atomic.Bool
+	rejected atomic.Int64
+}
+
+func (f *storageFaults) reject() error {
+	if !f.enabled.Load() {
+		return nil
+	}
+	f.rejected.Inc()
+	return ErrStorageReadOnly
+}
+
+// FaultyChunkManager wraps a chunk manager, it rejects every mutation with ErrStorageReadOnly while the storage is read-only.
+type FaultyChunkManager struct {
+	storage.ChunkManager
+	faults *storageFaults
+}
+
+func (cm *FaultyChunkManager) Write(ctx context.Context, filePath string, content []byte) error {
+	if err := cm.faults.reject(); err != nil {
+		return err
+	}
+	return cm.ChunkManager.Write(ctx, filePath, content)
+}
+
+func (cm *FaultyChunkManager) MultiWrite(ctx context.Context, contents map[string][]byte) error {
+	if err := cm.faults.reject(); err != nil {
+		return err
+	}
+	return cm.ChunkManager.MultiWrite(ctx, contents)
+}
+
+func (cm *FaultyChunkManager) Remove(ctx context.Context, filePath string) error {
+	if err := cm.faults.reject(); err != nil {
+		return err
+	}
+	return cm.ChunkManager.Remove(ctx, filePath)
+}
+
+func (cm *FaultyChunkManager) MultiRemove(ctx context.Context, filePaths []string) error {
+	if err := cm.faults.reject(); err != nil {
+		return err
+	}
+	return cm.ChunkManager.MultiRemove(ctx, filePaths)
+}
+
+func (cm *FaultyChunkManager) RemoveWithPrefix(ctx context.Context, prefix string) error {
+	if err := cm.faults.reject(); err != nil {
+		return err
+	}
+	return cm.ChunkManager.RemoveWithPrefix(ctx, prefix)
+}
+
+// faultyFactory hands out FaultyChunkManager as the persistent storage of the components.
+type faultyFactory struct {
+	dependency.Factory
+	faults *storageFaults
+}
+
+func (f *faultyFactory) NewPersistentStorageChunkManager(ctx context.Context) (storage.ChunkManager, error) {
+	cm, err := f.Factory.NewPersistentStorageChunkManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &FaultyChunkManager{ChunkManager: cm, faults: f.faults}, nil
+}
+
+// SetStorageReadOnly turns the persistent storage of the nodes read-only or back to writable.
+// Datacoord builds its own chunk manager, so it's not affected.
+// Note that a sync task of datanode or streamingnode panics once its write retries are exhausted,
+// so the storage shall not stay read-only for longer than the retry budget of sync while data is being flushed.
+func (cluster *MiniClusterV2) SetStorageReadOnly(readOnly bool) {
+	cluster.storageFaults.enabled.Store(readOnly)
+}
+
+// RejectedStorageWrites returns how many mutations have been rejected since the cluster started.
+func (cluster *MiniClusterV2) RejectedStorageWrites() int64 {
+	return cluster.storageFaults.rejected.Load()
+}
+
+// CheckSegmentBinlogsExist checks every binlog recorded in the meta of the segment exists in the storage,
+// which shall hold no matter how a flush fails.
+func (cluster *MiniClusterV2) CheckSegmentBinlogsExist(ctx context.Context, segment *datapb.SegmentInfo) error {
+	for _, p := range cluster.segmentBinlogPaths(segment) {
+		exist, err := cluster.ChunkManager.Exist(ctx, p)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return errors.Newf("binlog %s of segment %d is recorded in meta but missing in storage", p, segment.GetID())
+		}
+	}
+	return nil
+}
+
+// CompactAndCollectGarbage compacts the flushed segments of the collection in the default database manually,
+// then waits until garbage collection removes the binlogs of the compaction sources from storage,
+// while the binlogs of the compaction targets shall all stay.
+// Garbage collection shall be configured to run frequently with a small drop tolerance, see dataCoord.gc.
+func (cluster *MiniClusterV2) CompactAndCollectGarbage(ctx context.Context, collection string) error {
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return err
+	}
+	segments, err := cluster.MetaWatcher.ShowSegments()
+	if err != nil {
+		return err
+	}
+	// the meta of source segments is gone along with their binlogs, so record the paths beforehand
+	sourcePaths := make(map[int64][]string)
+	for _, segment := range segments {
+		if segment.GetCollectionID() == describeResp.GetCollectionID() && segment.GetState() == commonpb.SegmentState_Flushed {
+			sourcePaths[segment.GetID()] = cluster.segmentBinlogPaths(segment)
+		}
+	}
+
+	compactResp, err := cluster.Proxy.ManualCompaction(ctx, &milvuspb.ManualCompactionRequest{
+		CollectionID: describeResp.GetCollectionID(),
+	})
+	if err := merr.CheckRPCCall(compactResp, err); err != nil {
+		return err
+	}
+	var plans *milvuspb.GetCompactionPlansResponse
+	for {
+		plans, err = cluster.Proxy.GetCompactionStateWithPlans(ctx, &milvuspb.GetCompactionPlansRequest{
+			CompactionID: compactResp.GetCompactionID(),
+		})
+		if err := merr.CheckRPCCall(plans, err); err != nil {
+			return err
+		}
+		if plans.GetState() == commonpb.CompactionState_Completed {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "compaction %d of collection %s not completed", compactResp.GetCompactionID(), collection)
+		case <-time.After(time.Second):
+		}
+	}
+	if len(plans.GetMergeInfos()) == 0 {
+		return errors.Newf("compaction %d of collection %s compacts nothing", compactResp.GetCompactionID(), collection)
+	}
+
+	var pending, targets []int64
+	for _, info := range plans.GetMergeInfos() {
+		pending = append(pending, info.GetSources()...)
+		targets = append(targets, info.GetTarget())
+	}
+	for len(pending) > 0 {
+		var remaining []int64
+		for _, segmentID := range pending {
+			for _, p := range sourcePaths[segmentID] {
+				exist, err := cluster.ChunkManager.Exist(ctx, p)
+				if err != nil {
+					return err
+				}
+				if exist {
+					remaining = append(remaining, segmentID)
+					break
+				}
+			}
+		}
+		pending = remaining
+		if len(pending) == 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "binlogs of compacted segments %v are not collected", pending)
+		case <-time.After(time.Second):
+		}
+	}
+
+	segments, err = cluster.MetaWatcher.ShowSegments()
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if !lo.Contains(targets, segment.GetID()) {
+			continue
+		}
+		if err := cluster.CheckSegmentBinlogsExist(ctx, segment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// segmentBinlogPaths returns the paths of all binlogs, statslogs and deltalogs of the segment.
+func (cluster *MiniClusterV2) segmentBinlogPaths(segment *datapb.SegmentInfo) []string {
+	rootPath := cluster.ChunkManager.RootPath()
+	var paths []string
+	for _, fieldBinlog := range segment.GetBinlogs() {
+		for _, binlog := range fieldBinlog.GetBinlogs() {
+			paths = append(paths, logPathOr(binlog, metautil.BuildInsertLogPath(rootPath, segment.GetCollectionID(),
+				segment.GetPartitionID(), segment.GetID(), fieldBinlog.GetFieldID(), binlog.GetLogID())))
+		}
+	}
+	for _, fieldBinlog := range segment.GetStatslogs() {
+		for _, binlog := range fieldBinlog.GetBinlogs() {
+			paths = append(paths, logPathOr(binlog, metautil.BuildStatsLogPath(rootPath, segment.GetCollectionID(),
+				segment.GetPartitionID(), segment.GetID(), fieldBinlog.GetFieldID(), binlog.GetLogID())))
+		}
+	}
+	for _, fieldBinlog := range segment.GetDeltalogs() {
+		for _, binlog := range fieldBinlog.GetBinlogs() {
+			paths = append(paths, logPathOr(binlog, metautil.BuildDeltaLogPath(rootPath, segment.GetCollectionID(),
+				segment.GetPartitionID(), segment.GetID(), binlog.GetLogID())))
+		}
+	}
+	return paths
+}
+
+func logPathOr(binlog *datapb.Binlog, fallback string) string {
+	if binlog.GetLogPath() != "" {
+		return binlog.GetLogPath()
+	}
+	return fallback
+}
@@ -0,0 +1,179 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/distributed/streamingnode"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/streamingpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// getPChannelAssignment returns the streaming node the pchannel is assigned to, nil if it's not assigned yet.
+func (cluster *MiniClusterV2) getPChannelAssignment(pchannel string) (*streamingpb.PChannelMeta, error) {
+	pchannels, err := cluster.MetaWatcher.ShowPChannels()
+	if err != nil {
+		return nil, err
+	}
+	meta, ok := lo.Find(pchannels, func(meta *streamingpb.PChannelMeta) bool {
+		return meta.GetChannel().GetName() == pchannel
+	})
+	if !ok || meta.GetState() != streamingpb.PChannelMetaState_PCHANNEL_META_STATE_ASSIGNED {
+		return nil, nil
+	}
+	return meta, nil
+}
+
+// WriteDuringStreamingNodeFailover inserts into the loaded collection continuously,
+// and stops the streaming node serving the first channel of the collection in the middle.
+// It keeps writing until the channel fails over to another streaming node and more writes are acknowledged there,
+// then checks every acknowledged row is queryable. Writes failed during the failover are allowed.
+// The collection shall be of the schema by ConstructSchema without auto id. It returns the number of acknowledged rows.
+func (cluster *MiniClusterV2) WriteDuringStreamingNodeFailover(ctx context.Context, dbName, collection string, dim int) (int, error) {
+	const (
+		batch        = 100
+		ackedBatches = 5
+	)
+	describeResp, err := cluster.Proxy.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{
+		DbName:         dbName,
+		CollectionName: collection,
+	})
+	if err := merr.CheckRPCCall(describeResp, err); err != nil {
+		return 0, err
+	}
+	if len(describeResp.GetVirtualChannelNames()) == 0 {
+		return 0, errors.Newf("collection %s has no channel", collection)
+	}
+	pchannel := funcutil.ToPhysicalChannel(describeResp.GetVirtualChannelNames()[0])
+	before, err := cluster.getPChannelAssignment(pchannel)
+	if err != nil {
+		return 0, err
+	}
+	if before == nil {
+		return 0, errors.Newf("pchannel %s is not assigned", pchannel)
+	}
+	serving, ok := lo.Find(cluster.GetAllStreamingNodes(), func(node *streamingnode.Server) bool {
+		return node.GetAddress() == before.GetNode().GetAddress()
+	})
+	if !ok {
+		return 0, errors.Newf("streaming node %d serving pchannel %s not found", before.GetNode().GetServerId(), pchannel)
+	}
+
+	var (
+		mu    sync.Mutex
+		acked []int64
+		wg    sync.WaitGroup
+	)
+	ackedNum := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(acked)
+	}
+	writeCtx, stopWrite := context.WithCancel(ctx)
+	defer stopWrite()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for start := int64(0); writeCtx.Err() == nil; start += batch {
+			insertCtx, cancel := context.WithTimeout(writeCtx, 10*time.Second)
+			insertResult, err := cluster.Proxy.Insert(insertCtx, &milvuspb.InsertRequest{
+				DbName:         dbName,
+				CollectionName: collection,
+				FieldsData: []*schemapb.FieldData{
+					NewInt64FieldDataWithStart(Int64Field, batch, start),
+					NewFloatVectorFieldData(FloatVecField, batch, dim),
+				},
+				HashKeys: GenerateHashKeys(batch),
+				NumRows:  uint32(batch),
+			})
+			cancel()
+			if err := merr.CheckRPCCall(insertResult, err); err != nil {
+				log.Info("insert failed during streaming node failover", zap.Int64("start", start), zap.Error(err))
+				continue
+			}
+			mu.Lock()
+			acked = append(acked, insertResult.GetIDs().GetIntId().GetData()...)
+			mu.Unlock()
+		}
+	}()
+	waitAcked := func(n int) error {
+		for ackedNum() < n {
+			select {
+			case <-ctx.Done():
+				return errors.Wrapf(ctx.Err(), "only %d rows acknowledged, expected %d", ackedNum(), n)
+			case <-time.After(200 * time.Millisecond):
+			}
+		}
+		return nil
+	}
+
+	if err := waitAcked(ackedBatches * batch); err != nil {
+		return 0, err
+	}
+	log.Info("stop the streaming node serving the pchannel", zap.String("pchannel", pchannel),
+		zap.Int64("nodeID", before.GetNode().GetServerId()), zap.Int("acked", ackedNum()))
+	if err := serving.Stop(); err != nil {
+		return 0, err
+	}
+	cluster.untrackStreamingNode(serving)
+	for {
+		after, err := cluster.getPChannelAssignment(pchannel)
+		if err != nil {
+			return 0, err
+		}
+		if after != nil && after.GetNode().GetAddress() != before.GetNode().GetAddress() {
+			log.Info("pchannel failed over", zap.String("pchannel", pchannel), zap.Int64("nodeID", after.GetNode().GetServerId()))
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return 0, errors.Wrapf(ctx.Err(), "pchannel %s not failed over", pchannel)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+	if err := waitAcked(ackedNum() + ackedBatches*batch); err != nil {
+		return 0, err
+	}
+	stopWrite()
+	wg.Wait()
+
+	expr := fmt.Sprintf("%s in [%s]", Int64Field, strings.Join(lo.Map(acked, func(pk int64, _ int) string {
+		return strconv.FormatInt(pk, 10)
+	}), ","))
+	count, err := cluster.QueryCountWithDB(ctx, dbName, collection, expr)
+	if err != nil {
+		return 0, err
+	}
+	if count != int64(len(acked)) {
+		return 0, errors.Newf("%d rows acknowledged but only %d found after failover", len(acked), count)
+	}
+	return len(acked), nil
+}
@@ -0,0 +1,159 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/distributed/streamingnode"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// NodeTopology is a node tracked by the cluster.
+type NodeTopology struct {
+	// Name tells the node apart from the others of the role, e.g. "extra querynode 1".
+	Name string
+	Role string
+	// NodeID and Address are left empty if the node is missing or hasn't registered its session.
+	NodeID  int64
+	Address string
+	State   commonpb.StateCode
+	// Missing is set if the node isn't there at all, e.g. the proxy stopped by StopProxy.
+	Missing bool
+}
+
+// Topology is a snapshot of all nodes tracked by the cluster.
+type Topology struct {
+	Nodes []NodeTopology
+}
+
+// String formats the topology as a table, one node per line.
+func (t *Topology) String() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tROLE\tNODE ID\tADDRESS\tSTATE")
+	for _, node := range t.Nodes {
+		if node.Missing {
+			fmt.Fprintf(w, "%s\t%s\t-\t-\tMissing\n", node.Name, node.Role)
+			continue
+		}
+		address := node.Address
+		if address == "" {
+			address = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", node.Name, node.Role, node.NodeID, address, node.State.String())
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// ByRole returns the nodes of the role in the topology.
+func (t *Topology) ByRole(role string) []NodeTopology {
+	ret := make([]NodeTopology, 0)
+	for _, node := range t.Nodes {
+		if node.Role == role {
+			ret = append(ret, node)
+		}
+	}
+	return ret
+}
+
+type componentStatesGetter interface {
+	GetComponentStates(ctx context.Context, req *milvuspb.GetComponentStatesRequest) (*milvuspb.ComponentStates, error)
+}
+
+// DescribeTopology returns the coordinators, proxies, querynodes, datanodes and streaming nodes tracked by the cluster,
+// with their node ids and states reported by themselves and the addresses of their sessions, for failure logs of tests.
+// Stopped nodes which are still tracked are reported with their states, e.g. Abnormal.
+func (cluster *MiniClusterV2) DescribeTopology(ctx context.Context) (*Topology, error) {
+	sessions, err := cluster.MetaWatcher.ShowSessions()
+	if err != nil {
+		return nil, err
+	}
+	findSession := func(match func(session *sessionutil.SessionRaw) bool) *sessionutil.SessionRaw {
+		for _, session := range sessions {
+			if match(session) {
+				return session
+			}
+		}
+		return nil
+	}
+
+	topology := &Topology{}
+	track := func(name, role string, server componentStatesGetter, tracked bool) {
+		node := NodeTopology{Name: name, Role: role, Missing: !tracked}
+		if tracked {
+			node.State = commonpb.StateCode_Abnormal
+			if states, err := server.GetComponentStates(ctx, &milvuspb.GetComponentStatesRequest{}); err == nil {
+				node.NodeID = states.GetState().GetNodeID()
+				node.State = states.GetState().GetStateCode()
+			}
+			session := findSession(func(session *sessionutil.SessionRaw) bool {
+				return session.ServerName == role && session.ServerID == node.NodeID
+			})
+			if session != nil {
+				node.Address = session.Address
+			}
+		}
+		topology.Nodes = append(topology.Nodes, node)
+	}
+
+	track("rootcoord", typeutil.RootCoordRole, cluster.RootCoord, cluster.RootCoord != nil)
+	track("datacoord", typeutil.DataCoordRole, cluster.DataCoord, cluster.DataCoord != nil)
+	track("querycoord", typeutil.QueryCoordRole, cluster.QueryCoord, cluster.QueryCoord != nil)
+	track("main proxy", typeutil.ProxyRole, cluster.Proxy, cluster.Proxy != nil)
+	for i, node := range cluster.proxies {
+		track(fmt.Sprintf("extra proxy %d", i), typeutil.ProxyRole, node, true)
+	}
+	track("main querynode", typeutil.QueryNodeRole, cluster.QueryNode, cluster.QueryNode != nil)
+	for i, node := range cluster.querynodes {
+		track(fmt.Sprintf("extra querynode %d", i), typeutil.QueryNodeRole, node, true)
+	}
+	track("main datanode", typeutil.DataNodeRole, cluster.DataNode, cluster.DataNode != nil)
+	for i, node := range cluster.datanodes {
+		track(fmt.Sprintf("extra datanode %d", i), typeutil.DataNodeRole, node, true)
+	}
+
+	// streaming nodes don't serve the component states, they are found by the addresses of their sessions instead
+	trackStreamingNode := func(name string, server *streamingnode.Server) {
+		node := NodeTopology{Name: name, Role: typeutil.StreamingNodeRole, Missing: server == nil}
+		if server != nil {
+			node.State = server.Health(ctx)
+			node.Address = server.GetAddress()
+			session := findSession(func(session *sessionutil.SessionRaw) bool {
+				return session.ServerName == typeutil.StreamingNodeRole && session.Address == node.Address
+			})
+			if session != nil {
+				node.NodeID = session.ServerID
+			}
+		}
+		topology.Nodes = append(topology.Nodes, node)
+	}
+	if cluster.StreamingNode != nil || len(cluster.streamingnodes) > 0 {
+		trackStreamingNode("main streamingnode", cluster.StreamingNode)
+	}
+	for i, node := range cluster.streamingnodes {
+		trackStreamingNode(fmt.Sprintf("extra streamingnode %d", i), node)
+	}
+	return topology, nil
+}
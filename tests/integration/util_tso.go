@@ -0,0 +1,68 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/rootcoordpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// AllocTimestampBatches allocates n batches of batchSize timestamps from rootcoord,
+// it returns the first timestamp of every batch in allocation order.
+func (cluster *MiniClusterV2) AllocTimestampBatches(ctx context.Context, n int, batchSize uint32) ([]uint64, error) {
+	tss := make([]uint64, 0, n)
+	for i := 0; i < n; i++ {
+		resp, err := cluster.RootCoordClient.AllocTimestamp(ctx, &rootcoordpb.AllocTimestampRequest{
+			Count: batchSize,
+		})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return nil, err
+		}
+		if resp.GetCount() != batchSize {
+			return nil, errors.Newf("unexpected timestamp count, expected: %d, actual: %d", batchSize, resp.GetCount())
+		}
+		tss = append(tss, resp.GetTimestamp())
+	}
+	return tss, nil
+}
+
+// TimestampGaps returns the gaps between consecutive timestamps,
+// it fails if any timestamp is not greater than its predecessor.
+func TimestampGaps(tss []uint64) ([]uint64, error) {
+	if len(tss) == 0 {
+		return nil, nil
+	}
+	gaps := make([]uint64, 0, len(tss)-1)
+	for i := 1; i < len(tss); i++ {
+		if tss[i] <= tss[i-1] {
+			return nil, errors.Newf("timestamp not increasing at %d, prev: %d, current: %d", i, tss[i-1], tss[i])
+		}
+		gaps = append(gaps, tss[i]-tss[i-1])
+	}
+	return gaps, nil
+}
+
+// ApplyClockJump makes the clock seen by the timestamp oracle of rootcoord jump by jump at once,
+// a negative jump turns the clock backward. Jumps accumulate for the cluster, restarted rootcoords included.
+func (cluster *MiniClusterV2) ApplyClockJump(jump time.Duration) {
+	cluster.clockOffset.Add(jump)
+}